@@ -0,0 +1,362 @@
+// Package backup creates scheduled, password protected snapshots of all
+// virtual servers of a TeamSpeak 3 instance.
+//
+// Snapshots created with a password can't be read by the snapshot package,
+// use Unencrypted instead of Password to create backups which can be inspected.
+//
+// Snapshots are written to a Storage together with a manifest recording the
+// server ID, unique identifier, snapshot version and checksum of each backup.
+// Old backups are deleted according to the Retention rules, DefaultRetention
+// unless set with Retain or KeepAll. Virtual servers which aren't online are
+// skipped as the server can't create snapshots of them.
+//
+//	r, err := backup.New(c.Server, backup.Dir("/var/backups/ts3"),
+//		backup.Password("secret"),
+//		backup.Retain(7, 4),
+//	)
+//	sched, err := backup.ParseCron("0 3 * * *", nil)
+//	err = r.Run(ctx, sched)
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+// DefaultRetention is the Retention used if none is set.
+var DefaultRetention = Retention{Daily: 7, Weekly: 4}
+
+var (
+	// ErrNoPassword is returned by New if no password is set, use
+	// Unencrypted to create snapshots without one.
+	ErrNoPassword = errors.New("no password")
+
+	// ErrNoRetention is returned by Retain if both counts are zero, use
+	// KeepAll to keep every backup.
+	ErrNoRetention = errors.New("no backups retained")
+)
+
+// Server is the subset of ts3.ServerMethods used by a Runner.
+type Server interface {
+	List(options ...string) ([]*ts3.Server, error)
+	Use(id int) error
+	Whoami() (*ts3.ConnectionInfo, error)
+	SnapshotWrite(w io.Writer, password string) error
+}
+
+// Runner creates backups of all virtual servers.
+type Runner struct {
+	server      Server
+	storage     Storage
+	password    string
+	unencrypted bool
+	retention   Retention
+	onError     func(error)
+	now         func() time.Time
+
+	mtx sync.Mutex // mtx serialises backups and manifest updates.
+}
+
+// Password sets the password used to protect the snapshots.
+func Password(password string) func(*Runner) error {
+	return func(r *Runner) error {
+		r.password = password
+		r.unencrypted = false
+		return nil
+	}
+}
+
+// Unencrypted configures the Runner to create snapshots without a password,
+// so anyone with access to the storage can read and deploy them.
+func Unencrypted() func(*Runner) error {
+	return func(r *Runner) error {
+		r.password = ""
+		r.unencrypted = true
+		return nil
+	}
+}
+
+// Retain sets the number of daily and weekly backups kept for each virtual server.
+func Retain(daily, weekly int) func(*Runner) error {
+	return func(r *Runner) error {
+		if daily < 0 || weekly < 0 {
+			return fmt.Errorf("retain %d daily, %d weekly: negative count", daily, weekly)
+		}
+		if daily == 0 && weekly == 0 {
+			return ErrNoRetention
+		}
+		r.retention = Retention{Daily: daily, Weekly: weekly}
+		return nil
+	}
+}
+
+// KeepAll configures the Runner to never delete backups, so the storage
+// used grows without limit unless they are removed by other means.
+func KeepAll() func(*Runner) error {
+	return func(r *Runner) error {
+		r.retention = Retention{}
+		return nil
+	}
+}
+
+// Errors sets a function which is called with errors which occur while running.
+func Errors(f func(error)) func(*Runner) error {
+	return func(r *Runner) error {
+		r.onError = f
+		return nil
+	}
+}
+
+// New returns a new Runner which backs up the virtual servers of server to storage.
+func New(server Server, storage Storage, options ...func(*Runner) error) (*Runner, error) {
+	r := &Runner{
+		server:    server,
+		storage:   storage,
+		retention: DefaultRetention,
+		onError:   func(error) {},
+		now:       time.Now,
+	}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.password == "" && !r.unencrypted {
+		return nil, ErrNoPassword
+	}
+
+	return r, nil
+}
+
+// Manifest returns the manifest of the stored backups.
+func (r *Runner) Manifest() (*Manifest, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return loadManifest(r.storage)
+}
+
+// Backup creates a backup of each online virtual server, records them in the
+// manifest and deletes backups which are no longer retained. A failure to back
+// up one server doesn't prevent the others being backed up, the returned
+// entries are those which succeeded. If ctx is done no further servers are
+// backed up, but those already stored are still recorded. The previously
+// selected virtual server, if any, is selected again once done.
+func (r *Runner) Backup(ctx context.Context) (entries []*Entry, err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	m, err := loadManifest(r.storage)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := r.server.Whoami()
+	if err != nil {
+		return nil, fmt.Errorf("backup: whoami: %w", err)
+	}
+
+	if info.ServerID != 0 {
+		defer func() {
+			// Restore the previously selected server.
+			if err2 := r.server.Use(info.ServerID); err2 != nil && err == nil {
+				err = fmt.Errorf("backup: use %d: %w", info.ServerID, err2)
+			}
+		}()
+	}
+
+	servers, err := r.server.List(ts3.ServerListUID)
+	if err != nil {
+		return nil, fmt.Errorf("backup: list servers: %w", err)
+	}
+
+	var failed []string
+	var online int
+	var cancelled error
+	for _, s := range servers {
+		if err := ctx.Err(); err != nil {
+			cancelled = fmt.Errorf("backup: %w", err)
+			break
+		}
+
+		if s.Status != "online" {
+			continue
+		}
+		online++
+
+		e, err := r.backup(s)
+		if err != nil {
+			failed = append(failed, err.Error())
+			continue
+		}
+		entries = append(entries, e)
+		m.add(e)
+	}
+
+	if len(entries) > 0 {
+		if err := r.prune(m); err != nil {
+			return entries, err
+		}
+	}
+
+	if cancelled != nil {
+		return entries, cancelled
+	}
+
+	if len(failed) > 0 {
+		return entries, fmt.Errorf("backup: %d of %d servers failed: %s", len(failed), online, strings.Join(failed, "; "))
+	}
+
+	return entries, nil
+}
+
+// backup creates and stores a backup of s. The snapshot is streamed to the
+// storage, so it's never held in memory, and hashed as it's stored.
+func (r *Runner) backup(s *ts3.Server) (*Entry, error) {
+	if err := r.server.Use(s.ID); err != nil {
+		return nil, fmt.Errorf("backup: server %d: use: %w", s.ID, err)
+	}
+
+	created := r.now().UTC().Truncate(time.Second)
+	e := &Entry{
+		File:             fmt.Sprintf("%d-%s.snapshot.json", s.ID, created.Format("20060102T150405Z")),
+		ServerID:         s.ID,
+		UniqueIdentifier: s.UniqueIdentifier,
+		Name:             s.Name,
+		Created:          created,
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := r.server.SnapshotWrite(pw, r.password)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	h := sha256.New()
+	t := &tailWriter{}
+	err := r.storage.Put(e.File, io.TeeReader(pr, io.MultiWriter(h, t)))
+	// Unblock SnapshotWrite if the storage stopped reading early.
+	pr.Close()
+	werr := <-done
+	if err != nil && (werr == nil || errors.Is(werr, io.ErrClosedPipe)) {
+		// The storage failed, rather than the snapshot.
+		return nil, fmt.Errorf("backup: server %d: %w", s.ID, err)
+	}
+	if werr != nil {
+		if err == nil {
+			// The stored file is incomplete.
+			if derr := r.storage.Delete(e.File); derr != nil {
+				r.onError(fmt.Errorf("backup: delete %v: %w", e.File, derr))
+			}
+		}
+		return nil, fmt.Errorf("backup: server %d: %w", s.ID, werr)
+	}
+
+	// SnapshotWrite writes the version after the data.
+	if m := versionRe.FindSubmatch(t.tail); m != nil {
+		e.Version, _ = strconv.Atoi(string(m[1]))
+	}
+	e.Size = t.n
+	e.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	return e, nil
+}
+
+// tailWriter is an io.Writer which counts the bytes written and keeps the last of them.
+type tailWriter struct {
+	n    int64
+	tail []byte
+}
+
+// tailSize is the number of bytes kept by a tailWriter.
+const tailSize = 1 << 10
+
+// versionRe matches the snapshot version in the end of a snapshot file.
+var versionRe = regexp.MustCompile(`"version":(\d+)[,}]`)
+
+// Write implements io.Writer.
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	w.tail = append(w.tail, p...)
+	if len(w.tail) > tailSize {
+		w.tail = append(w.tail[:0], w.tail[len(w.tail)-tailSize:]...)
+	}
+
+	return len(p), nil
+}
+
+// prune deletes the backups of m which aren't retained and saves m.
+func (r *Runner) prune(m *Manifest) error {
+	expired := make(map[*Entry]bool)
+	for _, e := range r.retention.Expired(m.Entries) {
+		if err := r.storage.Delete(e.File); err != nil {
+			// Keep the entry so deletion is retried next time.
+			r.onError(fmt.Errorf("backup: delete %v: %w", e.File, err))
+			continue
+		}
+		expired[e] = true
+	}
+
+	entries := m.Entries[:0]
+	for _, e := range m.Entries {
+		if !expired[e] {
+			entries = append(entries, e)
+		}
+	}
+	m.Entries = entries
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("backup: save manifest: %w", err)
+	}
+
+	if err := r.storage.Put(ManifestName, &buf); err != nil {
+		return fmt.Errorf("backup: save manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Run runs backups according to sched until ctx is cancelled.
+// Errors from individual backups are passed to the Errors function.
+func (r *Runner) Run(ctx context.Context, sched Schedule) error {
+	for {
+		next := sched.Next(r.now())
+		if next.IsZero() {
+			return fmt.Errorf("backup: run: no next time: %w", ErrInvalidSchedule)
+		}
+
+		t := time.NewTimer(next.Sub(r.now()))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("backup: run: %w", ctx.Err())
+		case <-t.C:
+		}
+
+		if _, err := r.Backup(ctx); err != nil && ctx.Err() == nil {
+			r.onError(err)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a Server which returns a snapshot of the selected server.
+type fakeServer struct {
+	mtx      sync.Mutex
+	servers  []*ts3.Server
+	selected int
+	uses     []int
+	fail     map[int]bool
+	listOpts []string
+	password string
+	written  func() // written is called after each snapshot is written.
+}
+
+func (f *fakeServer) List(options ...string) ([]*ts3.Server, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.listOpts = options
+	return f.servers, nil
+}
+
+func (f *fakeServer) Use(id int) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.selected = id
+	f.uses = append(f.uses, id)
+	return nil
+}
+
+func (f *fakeServer) Whoami() (*ts3.ConnectionInfo, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return &ts3.ConnectionInfo{ServerID: f.selected}, nil
+}
+
+func (f *fakeServer) SnapshotWrite(w io.Writer, password string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.fail[f.selected] {
+		return &ts3.Error{ID: int(ts3.ErrPermissionClientInsufficient), Msg: "insufficient client permissions"}
+	}
+	f.password = password
+
+	// Like ts3.SnapshotWrite the version and salt follow the data.
+	_, err := fmt.Fprintf(w, `{"format":"ts3-snapshot","data":"server%d","version":3,"salt":"c2FsdA=="}`+"\n", f.selected)
+	if f.written != nil {
+		f.written()
+	}
+	return err
+}
+
+// clock is a fake time source.
+type clock struct {
+	mtx sync.Mutex
+	t   time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.t
+}
+
+func (c *clock) add(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.t = c.t.Add(d)
+}
+
+func newRunner(t *testing.T, server Server, storage Storage, c *clock, options ...func(*Runner) error) *Runner {
+	t.Helper()
+
+	r, err := New(server, storage, append([]func(*Runner) error{Password("secret")}, options...)...)
+	require.NoError(t, err)
+	r.now = c.now
+
+	return r
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&fakeServer{}, Dir(t.TempDir()))
+	assert.Equal(t, ErrNoPassword, err)
+
+	_, err = New(&fakeServer{}, Dir(t.TempDir()), Password("secret"), nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+
+	_, err = New(&fakeServer{}, Dir(t.TempDir()), Password("secret"), Retain(-1, 0))
+	assert.Error(t, err)
+
+	_, err = New(&fakeServer{}, Dir(t.TempDir()), Password("secret"), Retain(0, 0))
+	assert.Equal(t, ErrNoRetention, err)
+
+	r, err := New(&fakeServer{}, Dir(t.TempDir()), Password("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRetention, r.retention)
+
+	r, err = New(&fakeServer{}, Dir(t.TempDir()), Password("secret"), KeepAll())
+	require.NoError(t, err)
+	assert.Equal(t, Retention{}, r.retention)
+
+	r, err = New(&fakeServer{}, Dir(t.TempDir()), Unencrypted())
+	require.NoError(t, err)
+	assert.Empty(t, r.password)
+}
+
+func TestBackup(t *testing.T) {
+	server := &fakeServer{selected: 9, servers: []*ts3.Server{
+		{ID: 1, UniqueIdentifier: "uid1", Name: "One", Status: "online"},
+		{ID: 2, UniqueIdentifier: "uid2", Name: "Two", Status: "online"},
+		{ID: 3, UniqueIdentifier: "uid3", Name: "Three", Status: "offline"},
+	}}
+	storage := Dir(t.TempDir())
+	c := &clock{t: time.Date(2024, 3, 4, 3, 0, 0, 0, time.UTC)}
+	r := newRunner(t, server, storage, c, Retain(2, 0))
+
+	m, err := r.Manifest()
+	require.NoError(t, err)
+	assert.Empty(t, m.Entries)
+
+	entries, err := r.Backup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{ts3.ServerListUID}, server.listOpts)
+	assert.Equal(t, "secret", server.password)
+
+	// Offline servers are skipped and the previous selection restored.
+	assert.Equal(t, []int{1, 2, 9}, server.uses)
+	assert.Equal(t, 9, server.selected)
+
+	data := `{"format":"ts3-snapshot","data":"server1","version":3,"salt":"c2FsdA=="}` + "\n"
+	sum := sha256.Sum256([]byte(data))
+	require.Len(t, entries, 2)
+	assert.Equal(t, &Entry{
+		File:             "1-20240304T030000Z.snapshot.json",
+		ServerID:         1,
+		UniqueIdentifier: "uid1",
+		Name:             "One",
+		Version:          3,
+		Created:          c.now(),
+		Size:             int64(len(data)),
+		SHA256:           hex.EncodeToString(sum[:]),
+	}, entries[0])
+
+	rc, err := storage.Open(entries[0].File)
+	require.NoError(t, err)
+	stored, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, data, string(stored))
+
+	m, err = r.Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, entries, m.Entries)
+
+	// Only the latest two days are retained.
+	for i := 0; i < 2; i++ {
+		c.add(24 * time.Hour)
+		_, err = r.Backup(context.Background())
+		require.NoError(t, err)
+	}
+
+	names, err := storage.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"1-20240305T030000Z.snapshot.json",
+		"1-20240306T030000Z.snapshot.json",
+		"2-20240305T030000Z.snapshot.json",
+		"2-20240306T030000Z.snapshot.json",
+		ManifestName,
+	}, names)
+
+	m, err = r.Manifest()
+	require.NoError(t, err)
+	assert.Len(t, m.Entries, 4)
+
+	// A failing server doesn't prevent others being backed up.
+	server.fail = map[int]bool{1: true}
+	c.add(time.Hour)
+	entries, err = r.Backup(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 servers failed")
+	require.Len(t, entries, 1)
+	assert.Equal(t, 2, entries[0].ServerID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.Backup(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestBackupCancel(t *testing.T) {
+	server := &fakeServer{servers: []*ts3.Server{
+		{ID: 1, UniqueIdentifier: "uid1", Status: "online"},
+		{ID: 2, UniqueIdentifier: "uid2", Status: "online"},
+	}}
+	storage := Dir(t.TempDir())
+	r := newRunner(t, server, storage, &clock{t: time.Date(2024, 3, 4, 3, 0, 0, 0, time.UTC)})
+
+	// Cancelling after the first server stops the backup, but records it.
+	ctx, cancel := context.WithCancel(context.Background())
+	server.written = cancel
+	entries, err := r.Backup(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+	require.Len(t, entries, 1)
+	assert.Equal(t, 1, entries[0].ServerID)
+
+	m, err := r.Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, entries, m.Entries)
+}
+
+func TestBackupUnencrypted(t *testing.T) {
+	server := &fakeServer{password: "unset", servers: []*ts3.Server{{ID: 1, UniqueIdentifier: "uid1", Status: "online"}}}
+	r := newRunner(t, server, Dir(t.TempDir()), &clock{t: time.Date(2024, 3, 4, 3, 0, 0, 0, time.UTC)}, Unencrypted())
+
+	entries, err := r.Backup(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Empty(t, server.password)
+}
+
+func TestRun(t *testing.T) {
+	server := &fakeServer{servers: []*ts3.Server{{ID: 1, UniqueIdentifier: "uid1", Status: "online"}}}
+	storage := Dir(t.TempDir())
+	r, err := New(server, storage, Password("secret"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Run(ctx, Every(10*time.Millisecond))
+	}()
+
+	require.Eventually(t, func() bool {
+		m, err := r.Manifest()
+		return err == nil && len(m.Entries) > 0
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	assert.True(t, errors.Is(<-done, context.Canceled))
+
+	// Backups within the same second replace the previous one.
+	m, err := r.Manifest()
+	require.NoError(t, err)
+	files := make(map[string]bool)
+	for _, e := range m.Entries {
+		assert.False(t, files[e.File], e.File)
+		files[e.File] = true
+	}
+
+	never, err := ParseCron("0 0 30 2 *", nil)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(r.Run(context.Background(), never), ErrInvalidSchedule))
+}
+
+// failStorage is a Storage which fails to store any file.
+type failStorage struct {
+	Dir
+}
+
+func (failStorage) Put(name string, r io.Reader) error {
+	return fmt.Errorf("put %v: storage full", name)
+}
+
+func TestBackupStorageError(t *testing.T) {
+	server := &fakeServer{servers: []*ts3.Server{{ID: 1, UniqueIdentifier: "uid1", Status: "online"}}}
+	r := newRunner(t, server, failStorage{Dir(t.TempDir())}, &clock{t: time.Date(2024, 3, 4, 3, 0, 0, 0, time.UTC)})
+
+	// The snapshot isn't left blocked when the storage doesn't read it.
+	entries, err := r.Backup(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "storage full")
+	assert.Empty(t, entries)
+}
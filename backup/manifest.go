@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ManifestName is the name of the manifest file in the Storage.
+const ManifestName = "manifest.json"
+
+// Entry describes a backup in the manifest.
+type Entry struct {
+	File             string    `json:"file"`
+	ServerID         int       `json:"sid"`
+	UniqueIdentifier string    `json:"uid"`
+	Name             string    `json:"name"`
+	Version          int       `json:"version"`
+	Created          time.Time `json:"created"`
+	Size             int64     `json:"size"`
+	SHA256           string    `json:"sha256"` // SHA256 is the hex encoded checksum of the file.
+}
+
+// key returns the key which identifies the virtual server of the entry.
+func (e *Entry) key() string {
+	if e.UniqueIdentifier != "" {
+		return e.UniqueIdentifier
+	}
+	return "sid:" + strconv.Itoa(e.ServerID)
+}
+
+// Manifest lists the backups in a Storage.
+type Manifest struct {
+	Entries []*Entry `json:"entries"`
+}
+
+// add adds e to the manifest, replacing any entry for the same file.
+func (m *Manifest) add(e *Entry) {
+	for i, old := range m.Entries {
+		if old.File == e.File {
+			m.Entries[i] = e
+			return
+		}
+	}
+	m.Entries = append(m.Entries, e)
+}
+
+// loadManifest returns the manifest from s, an empty manifest if it doesn't exist.
+func loadManifest(s Storage) (*Manifest, error) {
+	rc, err := s.Open(ManifestName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Manifest{}, nil
+		}
+		return nil, fmt.Errorf("backup: load manifest: %w", err)
+	}
+	defer rc.Close() //nolint: errcheck
+
+	m := &Manifest{}
+	if err := json.NewDecoder(rc).Decode(m); err != nil {
+		return nil, fmt.Errorf("backup: load manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+// Retention determines which backups are kept for each virtual server.
+// The newest backup of each of the most recent Daily days and Weekly ISO
+// weeks is kept, a backup may satisfy both. If both are zero all backups are kept.
+type Retention struct {
+	Daily  int
+	Weekly int
+}
+
+// Expired returns the entries which aren't retained.
+func (r Retention) Expired(entries []*Entry) []*Entry {
+	if r.Daily <= 0 && r.Weekly <= 0 {
+		return nil
+	}
+
+	servers := make(map[string][]*Entry)
+	for _, e := range entries {
+		servers[e.key()] = append(servers[e.key()], e)
+	}
+
+	var expired []*Entry
+	for _, list := range servers {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Created.After(list[j].Created)
+		})
+
+		days := make(map[string]bool)
+		weeks := make(map[string]bool)
+		for _, e := range list {
+			t := e.Created.UTC()
+			var keep bool
+
+			day := t.Format("2006-01-02")
+			if !days[day] && len(days) < r.Daily {
+				days[day] = true
+				keep = true
+			}
+
+			year, week := t.ISOWeek()
+			wk := fmt.Sprintf("%d-W%02d", year, week)
+			if !weeks[wk] && len(weeks) < r.Weekly {
+				weeks[wk] = true
+				keep = true
+			}
+
+			if !keep {
+				expired = append(expired, e)
+			}
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].File < expired[j].File
+	})
+
+	return expired
+}
@@ -0,0 +1,61 @@
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionExpired(t *testing.T) {
+	// Entries every 12 hours for 3 weeks, from Monday 4th March 2024.
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	var entries []*Entry
+	for i := 0; i < 42; i++ {
+		created := start.Add(time.Duration(i) * 12 * time.Hour)
+		entries = append(entries, &Entry{
+			File:             "a-" + created.Format("20060102T150405Z"),
+			UniqueIdentifier: "a",
+			Created:          created,
+		})
+	}
+	// Another server is retained independently.
+	other := &Entry{File: "b", ServerID: 2, Created: start}
+	entries = append(entries, other)
+
+	assert.Nil(t, Retention{}.Expired(entries))
+
+	kept := func(r Retention) []string {
+		expired := make(map[*Entry]bool)
+		for _, e := range r.Expired(entries) {
+			expired[e] = true
+		}
+		assert.False(t, expired[other])
+
+		var files []string
+		for _, e := range entries {
+			if !expired[e] && e != other {
+				files = append(files, e.File)
+			}
+		}
+		return files
+	}
+
+	assert.Equal(t, []string{
+		"a-20240323T120000Z",
+		"a-20240324T120000Z",
+	}, kept(Retention{Daily: 2}))
+
+	assert.Equal(t, []string{
+		"a-20240310T120000Z",
+		"a-20240317T120000Z",
+		"a-20240324T120000Z",
+	}, kept(Retention{Weekly: 3}))
+
+	assert.Equal(t, []string{
+		"a-20240317T120000Z",
+		"a-20240322T120000Z",
+		"a-20240323T120000Z",
+		"a-20240324T120000Z",
+	}, kept(Retention{Daily: 3, Weekly: 2}))
+}
@@ -0,0 +1,192 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned by ParseCron if the spec is invalid.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Schedule determines when backups are run.
+type Schedule interface {
+	// Next returns the next time after t a backup should run.
+	Next(t time.Time) time.Time
+}
+
+// every is a Schedule which runs at a fixed interval.
+type every time.Duration
+
+// Every returns a Schedule which runs every d, aligned to multiples of d
+// since the zero time, so Every(time.Hour) runs on the hour.
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+// Next implements Schedule.
+func (e every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(e)).Add(time.Duration(e))
+}
+
+// cron is a Schedule using a cron expression.
+type cron struct {
+	minute, hour, dom, month, dow uint64 // bit sets of the allowed values.
+	domAny, dowAny                bool
+	loc                           *time.Location
+}
+
+// cronFields are the bounds of the cron fields.
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron returns a Schedule for the standard five field cron expression
+// spec: minute, hour, day of month, month and day of week. Fields support
+// *, values, ranges (1-5), lists (1,3) and steps (*/15). Day of week 0 and 7
+// are Sunday. As with cron, if both day of month and day of week are restricted
+// either matching runs the backup. Times are evaluated in loc, UTC if nil.
+func ParseCron(spec string, loc *time.Location) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("parse cron %q: expected %d fields: %w", spec, len(cronFields), ErrInvalidSchedule)
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var sets [5]uint64
+	for i, f := range fields {
+		set, err := parseCronField(f, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("parse cron %q: %s: %w", spec, cronFields[i].name, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday can be 0 or 7.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &cron{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+		loc:    loc,
+	}, nil
+}
+
+// parseCronField returns the bit set of the values of field f.
+func parseCronField(f string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("step %q: %w", part, ErrInvalidSchedule)
+			}
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			r := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(r[0])
+			hi, err2 = strconv.Atoi(r[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("range %q: %w", part, ErrInvalidSchedule)
+			}
+		default:
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("value %q: %w", part, ErrInvalidSchedule)
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d: %w", part, min, max, ErrInvalidSchedule)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+// has returns true if v is in set.
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
+
+// dayMatches returns true if the day of t matches the schedule.
+func (c *cron) dayMatches(t time.Time) bool {
+	dom, dow := has(c.dom, t.Day()), has(c.dow, int(t.Weekday()))
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// Next implements Schedule.
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.In(c.loc).Truncate(time.Minute).Add(time.Minute)
+
+	// Give up after five years, the spec can't match e.g. 30th February.
+	end := t.AddDate(5, 0, 0)
+	for t.Before(end) {
+		if !has(c.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+			continue
+		}
+
+		if !has(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.loc)
+			continue
+		}
+
+		if !has(c.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
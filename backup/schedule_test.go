@@ -0,0 +1,71 @@
+package backup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvery(t *testing.T) {
+	s := Every(time.Hour)
+	start := time.Date(2024, 3, 1, 10, 20, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), s.Next(start))
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), s.Next(s.Next(start)))
+}
+
+func TestParseCron(t *testing.T) {
+	// Friday 1st March 2024.
+	start := time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, 3, 1, 10, 21, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 3, 2, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)},
+		{"5,50 10 * * *", time.Date(2024, 3, 1, 10, 50, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)},
+		{"30 2 * * 0", time.Date(2024, 3, 3, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * 7", time.Date(2024, 3, 3, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 1", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC).AddDate(4, 0, 0)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			s, err := ParseCron(tc.spec, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.next, s.Next(start))
+		})
+	}
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	s, err := ParseCron("0 3 * * *", loc)
+	require.NoError(t, err)
+	assert.True(t, time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC).Equal(s.Next(start)))
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"a * * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1-x * * * *",
+	} {
+		_, err := ParseCron(spec, nil)
+		assert.True(t, errors.Is(err, ErrInvalidSchedule), "%q: %v", spec, err)
+	}
+}
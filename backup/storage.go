@@ -0,0 +1,118 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Storage stores backup files.
+type Storage interface {
+	// Put stores the contents of r as the file name, replacing any existing file.
+	Put(name string, r io.Reader) error
+
+	// Open returns the contents of the file name. If it doesn't exist the
+	// error must satisfy errors.Is(err, fs.ErrNotExist).
+	Open(name string) (io.ReadCloser, error)
+
+	// Delete deletes the file name.
+	Delete(name string) error
+
+	// List returns the names of the stored files.
+	List() ([]string, error)
+}
+
+// Dir is a Storage which stores files in a local directory.
+type Dir string
+
+// Put implements Storage. The file is written to a temporary file which is
+// renamed once complete so a partial file is never visible.
+func (d Dir) Put(name string, r io.Reader) (err error) {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(string(d), 0o700); err != nil {
+		return fmt.Errorf("dir: put %v: %w", name, err)
+	}
+
+	f, err := os.CreateTemp(string(d), ".tmp-"+name+"-*")
+	if err != nil {
+		return fmt.Errorf("dir: put %v: %w", name, err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()           //nolint: errcheck
+			os.Remove(f.Name()) //nolint: errcheck
+		}
+	}()
+
+	if _, err = io.Copy(f, r); err != nil {
+		return fmt.Errorf("dir: put %v: %w", name, err)
+	}
+
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("dir: put %v: %w", name, err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("dir: put %v: %w", name, err)
+	}
+
+	if err = os.Rename(f.Name(), filepath.Join(string(d), name)); err != nil {
+		return fmt.Errorf("dir: put %v: %w", name, err)
+	}
+
+	return nil
+}
+
+// Open implements Storage.
+func (d Dir) Open(name string) (io.ReadCloser, error) {
+	if err := validName(name); err != nil {
+		return nil, err
+	}
+
+	return os.Open(filepath.Join(string(d), name))
+}
+
+// Delete implements Storage.
+func (d Dir) Delete(name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	return os.Remove(filepath.Join(string(d), name))
+}
+
+// List implements Storage. Temporary files are excluded.
+func (d Dir) List() ([]string, error) {
+	entries, err := os.ReadDir(string(d))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("dir: list: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".tmp-") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// validName returns an error if name isn't a valid file name for a Dir.
+func validName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return &fs.PathError{Op: "dir", Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package backup
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	d := Dir(filepath.Join(t.TempDir(), "backups"))
+
+	names, err := d.List()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = d.Open("missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+
+	require.NoError(t, d.Put("b", strings.NewReader("second")))
+	require.NoError(t, d.Put("a", strings.NewReader("first")))
+	require.NoError(t, d.Put("a", strings.NewReader("replaced")))
+
+	// Temporary files aren't listed.
+	require.NoError(t, os.WriteFile(filepath.Join(string(d), ".tmp-c-1"), nil, 0o600))
+
+	names, err = d.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	rc, err := d.Open("a")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "replaced", string(data))
+
+	require.NoError(t, d.Delete("b"))
+	names, err = d.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, names)
+
+	for _, name := range []string{"", ".", "..", "../a", `a\b`} {
+		assert.True(t, errors.Is(d.Put(name, strings.NewReader("")), fs.ErrInvalid), name)
+		_, err = d.Open(name)
+		assert.True(t, errors.Is(err, fs.ErrInvalid), name)
+		assert.True(t, errors.Is(d.Delete(name), fs.ErrInvalid), name)
+	}
+}
@@ -0,0 +1,131 @@
+package ts3
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrEmptyBanRule is returned by BanAdd if the rule doesn't match anything.
+var ErrEmptyBanRule = errors.New("ban rule requires an ip, name or uid")
+
+// Ban represents a ban rule on a virtual server.
+type Ban struct {
+	ID                int           `ms:"banid"`
+	IP                string        `ms:"ip"`
+	Name              string        `ms:"name"`
+	UniqueIdentifier  string        `ms:"uid"`
+	MyTSID            string        `ms:"mytsid"`
+	LastNickname      string        `ms:"lastnickname"`
+	Created           time.Time     `ms:"created"`
+	Duration          time.Duration `ms:"duration"` // Zero for permanent bans.
+	InvokerName       string        `ms:"invokername"`
+	InvokerDatabaseID int           `ms:"invokercldbid"`
+	InvokerUID        string        `ms:"invokeruid"`
+	Reason            string        `ms:"reason"`
+	Enforcements      int           `ms:"enforcements"`
+}
+
+// Permanent returns true if the ban never expires.
+func (b *Ban) Permanent() bool {
+	return b.Duration == 0
+}
+
+// Expires returns the time the ban expires or the zero time if it's permanent.
+func (b *Ban) Expires() time.Time {
+	if b.Permanent() {
+		return time.Time{}
+	}
+	return b.Created.Add(b.Duration)
+}
+
+// BanList returns the active ban rules of the selected server.
+func (s *ServerMethods) BanList() ([]*Ban, error) {
+	var bans []*Ban
+	if _, err := s.ExecCmd(NewCmd("banlist").WithResponse(&bans)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return bans, nil
+}
+
+// BanRule describes a new ban rule.
+// At least one of IP, Name or UniqueIdentifier must be set.
+type BanRule struct {
+	IP               string // IP is a regular expression matched against the clients IP.
+	Name             string // Name is a regular expression matched against the clients nickname.
+	UniqueIdentifier string
+	Duration         time.Duration // Duration is rounded down to seconds, zero is permanent.
+	Reason           string
+}
+
+// BanAdd adds a new ban rule to the selected server and returns its ID.
+func (s *ServerMethods) BanAdd(rule *BanRule) (int, error) {
+	var args []CmdArg
+	if rule.IP != "" {
+		args = append(args, NewArg("ip", rule.IP))
+	}
+	if rule.Name != "" {
+		args = append(args, NewArg("name", rule.Name))
+	}
+	if rule.UniqueIdentifier != "" {
+		args = append(args, NewArg("uid", rule.UniqueIdentifier))
+	}
+	if len(args) == 0 {
+		return 0, ErrEmptyBanRule
+	}
+	args = append(args, banArgs(rule.Duration, rule.Reason)...)
+
+	r := struct {
+		ID int `ms:"banid"`
+	}{}
+	_, err := s.ExecCmd(NewCmd("banadd").WithArgs(args...).WithResponse(&r))
+	return r.ID, err
+}
+
+// BanClient bans the online client clid, kicking it from the server, and
+// returns the IDs of the ban rules created.
+// A zero duration is a permanent ban.
+func (s *ServerMethods) BanClient(clid int, duration time.Duration, reason string) ([]int, error) {
+	args := append([]CmdArg{NewArg("clid", clid)}, banArgs(duration, reason)...)
+
+	var r []*struct {
+		ID int `ms:"banid"`
+	}
+	if _, err := s.ExecCmd(NewCmd("banclient").WithArgs(args...).WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(r))
+	for i, b := range r {
+		ids[i] = b.ID
+	}
+
+	return ids, nil
+}
+
+// BanDel deletes the ban rule id from the selected server.
+func (s *ServerMethods) BanDel(id int) error {
+	_, err := s.ExecCmd(NewCmd("bandel").WithArgs(NewArg("banid", id)))
+	return err
+}
+
+// BanDelAll deletes all active ban rules from the selected server.
+func (s *ServerMethods) BanDelAll() error {
+	_, err := s.Exec("bandelall")
+	return err
+}
+
+// banArgs returns the time and banreason args for a ban.
+func banArgs(duration time.Duration, reason string) []CmdArg {
+	var args []CmdArg
+	if duration > 0 {
+		args = append(args, NewArg("time", int64(duration/time.Second)))
+	}
+	if reason != "" {
+		args = append(args, NewArg("banreason", reason))
+	}
+	return args
+}
@@ -0,0 +1,134 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsBan(t *testing.T) {
+	var empty bool
+	s := newServer(t, handler("banlist", func(string) string {
+		if empty {
+			return `error id=1281 msg=database\sempty\sresult\sset`
+		}
+		return commands["banlist"]
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	banlist := func(t *testing.T) {
+		t.Helper()
+		bans, err := c.Server.BanList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*Ban{
+			{
+				ID:                5,
+				IP:                "1.2.3.4",
+				LastNickname:      "Jeff",
+				Created:           time.Unix(1259147468, 0),
+				Duration:          time.Hour,
+				InvokerName:       "serveradmin",
+				InvokerDatabaseID: 1,
+				InvokerUID:        "serveradmin",
+				Reason:            "spam",
+				Enforcements:      2,
+			},
+			{
+				ID:                6,
+				UniqueIdentifier:  "DZhdQU58qyooEK4Fr8Ly738hEmc=",
+				LastNickname:      "bdeb1337",
+				Created:           time.Unix(1259147468, 0),
+				InvokerName:       "serveradmin",
+				InvokerDatabaseID: 1,
+				InvokerUID:        "serveradmin",
+			},
+		}
+		assert.Equal(t, expected, bans)
+
+		assert.False(t, bans[0].Permanent())
+		assert.Equal(t, time.Unix(1259147468+3600, 0), bans[0].Expires())
+		assert.True(t, bans[1].Permanent())
+		assert.True(t, bans[1].Expires().IsZero())
+	}
+
+	banadd := func(t *testing.T) {
+		t.Helper()
+		id, err := c.Server.BanAdd(&BanRule{Name: ".*jeff.*", Duration: time.Hour, Reason: "spam"})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 7, id)
+
+		_, err = c.Server.BanAdd(&BanRule{Reason: "nothing"})
+		assert.Equal(t, ErrEmptyBanRule, err)
+	}
+
+	banclient := func(t *testing.T) {
+		t.Helper()
+		ids, err := c.Server.BanClient(42087, 0, "")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []int{8, 9}, ids)
+	}
+
+	banlistempty := func(t *testing.T) {
+		t.Helper()
+		empty = true
+		defer func() { empty = false }()
+		bans, err := c.Server.BanList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Nil(t, bans)
+	}
+
+	bandel := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.BanDel(5))
+	}
+
+	bandelall := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.BanDelAll())
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"banlist", banlist},
+		{"banlistempty", banlistempty},
+		{"banadd", banadd},
+		{"banclient", banclient},
+		{"bandel", bandel},
+		{"bandelall", bandelall},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestBanArgs(t *testing.T) {
+	assert.Empty(t, banArgs(0, ""))
+	args := banArgs(time.Minute+time.Millisecond, "too much spam")
+	if assert.Len(t, args, 2) {
+		assert.Equal(t, "time=60", args[0].ArgString())
+		assert.Equal(t, `banreason=too\smuch\sspam`, args[1].ArgString())
+	}
+}
@@ -104,7 +104,7 @@ func (c *Client) SetChannelCommander(val bool) error {
 	return c.ClientUpdate(NewArg(ClientIsChannelCommander, val))
 }
 
-// SetIcon sets the clients icon based on the CRC32 checksum.
+// SetIcon sets the clients icon, id is the CRC32 checksum of the icon as returned by IconID.
 func (c *Client) SetIcon(id int) error {
 	return c.ClientUpdate(NewArg(ClientIconID, id))
 }
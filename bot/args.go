@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrUnterminatedQuote is returned by SplitArgs if a quoted argument isn't closed.
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+// SplitArgs splits s into arguments separated by whitespace.
+// Arguments containing whitespace can be quoted with double quotes, or single
+// quotes at the start of an argument so apostrophes such as in "don't" are kept,
+// and a backslash escapes the following character.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var b strings.Builder
+	var quote rune
+	var escaped, inArg bool
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '"' || r == '\'' && !inArg:
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		default:
+			b.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, ErrUnterminatedQuote
+	}
+
+	if escaped {
+		// Trailing backslash is taken literally.
+		b.WriteRune('\\')
+	}
+
+	if inArg {
+		args = append(args, b.String())
+	}
+
+	return args, nil
+}
@@ -0,0 +1,33 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected []string
+		err      error
+	}{
+		{"", nil, nil},
+		{"  a  b\tc ", []string{"a", "b", "c"}, nil},
+		{`"hello world" x`, []string{"hello world", "x"}, nil},
+		{`'it"s' ""`, []string{`it"s`, ""}, nil},
+		{`a\ b c\"d`, []string{"a b", `c"d`}, nil},
+		{`pre"fix suf"fix`, []string{"prefix suffix"}, nil},
+		{`say don't 'stop now'`, []string{"say", "don't", "stop now"}, nil},
+		{`trailing\`, []string{`trailing\`}, nil},
+		{`"open`, nil, ErrUnterminatedQuote},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			args, err := SplitArgs(tc.in)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Package bot provides a chat command router for TeamSpeak 3 query bots.
+//
+// A Router consumes textmessage notifications, parses messages of the form
+// `!command arg1 "arg 2"` and dispatches them to the registered Command.
+// Commands can be restricted to members of specific server groups and
+// handlers can answer using Request.Reply, which responds to the invoker in
+// the same target mode the command was received in.
+//
+// The client must be registered for the text events the bot should respond to,
+// for example:
+//
+//	c.Register(ts3.TextPrivateEvents)
+//	c.Register(ts3.TextChannelEvents)
+//	r.Run(ctx, c.Notifications())
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+// DefaultPrefix is the default command prefix.
+const DefaultPrefix = "!"
+
+var (
+	// ErrInvalidCommand is returned by Router.Handle if a command has no name or handler.
+	ErrInvalidCommand = errors.New("invalid command")
+
+	// ErrDuplicateCommand is returned by Router.Handle if a command name or alias is already registered.
+	ErrDuplicateCommand = errors.New("duplicate command")
+
+	// ErrPermissionDenied is passed to the ErrorHandler if the invoker isn't
+	// a member of any of the server groups required by a command.
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrUsage is passed, wrapped with the usage of the command, to the ErrorHandler
+	// if the arguments of a command are invalid.
+	ErrUsage = errors.New("invalid arguments")
+)
+
+// Server is the subset of ts3.ServerMethods used by a Router.
+type Server interface {
+	Whoami() (*ts3.ConnectionInfo, error)
+	SendTextMessage(mode ts3.TargetMode, target int, msg string) error
+	ClientGetDBIDFromUID(uid string) (int, error)
+	ServerGroupsByClientID(cldbid int) ([]*ts3.ClientServerGroup, error)
+}
+
+// HandlerFunc handles a command request.
+type HandlerFunc func(req *Request) error
+
+// ErrorHandler handles an error which occurred while processing req.
+type ErrorHandler func(req *Request, err error)
+
+// Command is a chat command.
+type Command struct {
+	// Name is the name used to invoke the command, matched case insensitively.
+	Name string
+
+	// Aliases are alternative names for the command.
+	Aliases []string
+
+	// Usage describes the arguments of the command, e.g. `<clid> [reason]`.
+	Usage string
+
+	// MinArgs is the minimum number of arguments the command requires.
+	MinArgs int
+
+	// ServerGroups, if not empty, restricts the command to members of at
+	// least one of the server groups.
+	ServerGroups []int
+
+	// Handler is called to handle the command.
+	Handler HandlerFunc
+}
+
+// Request is a parsed command invocation.
+type Request struct {
+	// Command is the command being invoked.
+	Command *Command
+
+	// Name is the name, or alias, the command was invoked with.
+	Name string
+
+	// Args are the parsed arguments.
+	Args []string
+
+	// Mode is the target mode the message was received in.
+	Mode ts3.TargetMode
+
+	// InvokerID, InvokerName and InvokerUID identify the client which sent the message.
+	InvokerID   int
+	InvokerName string
+	InvokerUID  string
+
+	server Server
+}
+
+// Reply sends msg in response to the request.
+// Private messages are answered privately to the invoker, channel and server
+// messages are answered in the channel or server respectively.
+func (req *Request) Reply(msg string) error {
+	target := req.InvokerID
+	if req.Mode != ts3.TargetClient {
+		target = 0
+	}
+
+	return req.server.SendTextMessage(req.Mode, target, msg)
+}
+
+// Replyf formats according to a format specifier and sends the result using Reply.
+func (req *Request) Replyf(format string, args ...interface{}) error {
+	return req.Reply(fmt.Sprintf(format, args...))
+}
+
+// Arg returns the argument i or an empty string if not present.
+func (req *Request) Arg(i int) string {
+	if i < 0 || i >= len(req.Args) {
+		return ""
+	}
+	return req.Args[i]
+}
+
+// IntArg returns the argument i as an int.
+func (req *Request) IntArg(i int) (int, error) {
+	v, err := strconv.Atoi(req.Arg(i))
+	if err != nil {
+		return 0, fmt.Errorf("%w: argument %d must be a number", ErrUsage, i+1)
+	}
+	return v, nil
+}
+
+// DefaultErrorHandler replies to the request with the error message.
+func DefaultErrorHandler(req *Request, err error) {
+	_ = req.Reply("Error: " + err.Error())
+}
+
+// Router dispatches chat commands to their handlers.
+type Router struct {
+	server       Server
+	prefix       string
+	errorHandler ErrorHandler
+
+	mtx      sync.RWMutex
+	commands map[string]*Command
+	self     int // self is the client ID of the query client, if known.
+}
+
+// Prefix sets the prefix which identifies a message as a command, default DefaultPrefix.
+func Prefix(prefix string) func(*Router) error {
+	return func(r *Router) error {
+		if prefix == "" {
+			return errors.New("bot: empty prefix")
+		}
+		r.prefix = prefix
+		return nil
+	}
+}
+
+// Errors sets the handler called for errors, default DefaultErrorHandler.
+func Errors(h ErrorHandler) func(*Router) error {
+	return func(r *Router) error {
+		r.errorHandler = h
+		return nil
+	}
+}
+
+// New returns a new Router which uses server to respond to commands.
+func New(server Server, options ...func(*Router) error) (*Router, error) {
+	r := &Router{
+		server:       server,
+		prefix:       DefaultPrefix,
+		errorHandler: DefaultErrorHandler,
+		commands:     make(map[string]*Command),
+	}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Handle registers cmd.
+func (r *Router) Handle(cmd *Command) error {
+	if cmd.Name == "" || cmd.Handler == nil {
+		return fmt.Errorf("bot: command %q: %w", cmd.Name, ErrInvalidCommand)
+	}
+
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for _, n := range names {
+		if _, ok := r.commands[strings.ToLower(n)]; ok {
+			return fmt.Errorf("bot: command %q: %w", n, ErrDuplicateCommand)
+		}
+	}
+
+	for _, n := range names {
+		r.commands[strings.ToLower(n)] = cmd
+	}
+
+	return nil
+}
+
+// HandleFunc registers h as the handler for the command name, without restrictions.
+func (r *Router) HandleFunc(name string, h HandlerFunc) error {
+	return r.Handle(&Command{Name: name, Handler: h})
+}
+
+// Commands returns the registered commands ordered by name.
+func (r *Router) Commands() []*Command {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	seen := make(map[*Command]bool, len(r.commands))
+	cmds := make([]*Command, 0, len(r.commands))
+	for _, c := range r.commands {
+		if !seen[c] {
+			seen[c] = true
+			cmds = append(cmds, c)
+		}
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].Name < cmds[j].Name
+	})
+
+	return cmds
+}
+
+// Run handles notifications from ch until ctx is done or ch is closed.
+// Messages sent by the query client itself are ignored.
+func (r *Router) Run(ctx context.Context, ch <-chan ts3.Notification) error {
+	info, err := r.server.Whoami()
+	if err != nil {
+		return fmt.Errorf("bot: whoami: %w", err)
+	}
+
+	r.mtx.Lock()
+	r.self = info.ClientID
+	r.mtx.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("bot: run: %w", ctx.Err())
+		case n, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			r.Dispatch(n)
+		}
+	}
+}
+
+// Dispatch calls the handler of the command in the textmessage notification n.
+// Other notifications and messages which aren't commands are ignored.
+func (r *Router) Dispatch(n ts3.Notification) {
+	if n.Type != "textmessage" {
+		return
+	}
+
+	msg := strings.TrimSpace(n.Data["msg"])
+	if !strings.HasPrefix(msg, r.prefix) {
+		return
+	}
+
+	invokerID, _ := strconv.Atoi(n.Data["invokerid"])
+	mode, _ := strconv.Atoi(n.Data["targetmode"])
+
+	r.mtx.RLock()
+	self := r.self
+	r.mtx.RUnlock()
+	if self != 0 && invokerID == self {
+		return
+	}
+
+	line := strings.TrimPrefix(msg, r.prefix)
+	name := line
+	var rest string
+	if i := strings.IndexFunc(line, unicode.IsSpace); i != -1 {
+		name, rest = line[:i], line[i:]
+	}
+
+	r.mtx.RLock()
+	cmd, ok := r.commands[strings.ToLower(name)]
+	r.mtx.RUnlock()
+	if !ok {
+		// Unknown commands may be intended for another bot.
+		return
+	}
+
+	req := &Request{
+		Command:     cmd,
+		Name:        name,
+		Mode:        ts3.TargetMode(mode),
+		InvokerID:   invokerID,
+		InvokerName: n.Data["invokername"],
+		InvokerUID:  n.Data["invokeruid"],
+		server:      r.server,
+	}
+
+	if err := r.dispatch(req, rest); err != nil {
+		r.errorHandler(req, err)
+	}
+}
+
+// dispatch checks permissions, parses the arguments and calls the handler of req.
+func (r *Router) dispatch(req *Request, rest string) error {
+	if err := r.authorize(req); err != nil {
+		return err
+	}
+
+	args, err := SplitArgs(rest)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUsage, err)
+	}
+	req.Args = args
+
+	if len(args) < req.Command.MinArgs {
+		usage := strings.TrimSpace(r.prefix + req.Command.Name + " " + req.Command.Usage)
+		return fmt.Errorf("%w: usage: %s", ErrUsage, usage)
+	}
+
+	return req.Command.Handler(req)
+}
+
+// authorize returns ErrPermissionDenied if the invoker of req isn't allowed to use its command.
+func (r *Router) authorize(req *Request) error {
+	if len(req.Command.ServerGroups) == 0 {
+		return nil
+	}
+
+	dbid, err := r.server.ClientGetDBIDFromUID(req.InvokerUID)
+	if err != nil {
+		return fmt.Errorf("bot: client database id: %w", err)
+	}
+
+	groups, err := r.server.ServerGroupsByClientID(dbid)
+	if err != nil {
+		return fmt.Errorf("bot: server groups: %w", err)
+	}
+
+	for _, g := range groups {
+		for _, id := range req.Command.ServerGroups {
+			if g.ID == id {
+				return nil
+			}
+		}
+	}
+
+	return ErrPermissionDenied
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer records the messages sent by a Router.
+type fakeServer struct {
+	sent   []string
+	dbids  []string
+	groups map[string][]int
+}
+
+func (f *fakeServer) Whoami() (*ts3.ConnectionInfo, error) {
+	return &ts3.ConnectionInfo{ClientID: 1}, nil
+}
+
+func (f *fakeServer) SendTextMessage(mode ts3.TargetMode, target int, msg string) error {
+	f.sent = append(f.sent, fmt.Sprintf("%d:%d %s", mode, target, msg))
+	return nil
+}
+
+// ClientGetDBIDFromUID returns the index of uid in dbids plus one.
+func (f *fakeServer) ClientGetDBIDFromUID(uid string) (int, error) {
+	for i, u := range f.dbids {
+		if u == uid {
+			return i + 1, nil
+		}
+	}
+	return 0, errors.New("database empty result set")
+}
+
+func (f *fakeServer) ServerGroupsByClientID(cldbid int) ([]*ts3.ClientServerGroup, error) {
+	ids := f.groups[f.dbids[cldbid-1]]
+	groups := make([]*ts3.ClientServerGroup, len(ids))
+	for i, id := range ids {
+		groups[i] = &ts3.ClientServerGroup{ID: id, DatabaseID: cldbid}
+	}
+	return groups, nil
+}
+
+func message(mode ts3.TargetMode, invoker int, uid, msg string) ts3.Notification {
+	return ts3.Notification{Type: "textmessage", Data: map[string]string{
+		"targetmode":  fmt.Sprint(int(mode)),
+		"msg":         msg,
+		"invokerid":   fmt.Sprint(invoker),
+		"invokername": "someone",
+		"invokeruid":  uid,
+	}}
+}
+
+func newRouter(t *testing.T, srv *fakeServer, options ...func(*Router) error) *Router {
+	t.Helper()
+	r, err := New(srv, options...)
+	require.NoError(t, err)
+
+	require.NoError(t, r.HandleFunc("echo", func(req *Request) error {
+		return req.Reply(strings.Join(req.Args, ","))
+	}))
+	require.NoError(t, r.Handle(&Command{
+		Name:         "kick",
+		Aliases:      []string{"k"},
+		Usage:        "<clid> [reason]",
+		MinArgs:      1,
+		ServerGroups: []int{6},
+		Handler: func(req *Request) error {
+			clid, err := req.IntArg(0)
+			if err != nil {
+				return err
+			}
+			return req.Replyf("kicked %d: %s", clid, req.Arg(1))
+		},
+	}))
+	require.NoError(t, r.HandleFunc("fail", func(req *Request) error {
+		return errors.New("boom")
+	}))
+
+	return r
+}
+
+func TestRouter(t *testing.T) {
+	srv := &fakeServer{
+		dbids:  []string{"admin", "guest"},
+		groups: map[string][]int{"admin": {8, 6}, "guest": {8}},
+	}
+	r := newRouter(t, srv)
+
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", `!echo a "b c"`))
+	r.Dispatch(message(ts3.TargetChannel, 5, "guest", "!ECHO x"))
+	r.Dispatch(message(ts3.TargetServer, 5, "guest", "!echo"))
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", "hello"))
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", "!unknown"))
+	r.Dispatch(ts3.Notification{Type: "cliententerview"})
+	r.Dispatch(message(ts3.TargetClient, 6, "admin", `!k 42 "bad language"`))
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", "!kick 42"))
+	r.Dispatch(message(ts3.TargetClient, 7, "nobody", "!kick 42"))
+	r.Dispatch(message(ts3.TargetClient, 6, "admin", "!kick"))
+	r.Dispatch(message(ts3.TargetClient, 6, "admin", "!kick x"))
+	r.Dispatch(message(ts3.TargetClient, 6, "admin", `!kick "x`))
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", "!fail"))
+
+	expected := []string{
+		"1:5 a,b c",
+		"2:0 x",
+		"3:0 ",
+		"1:6 kicked 42: bad language",
+		"1:5 Error: permission denied",
+		"1:7 Error: bot: client database id: database empty result set",
+		"1:6 Error: invalid arguments: usage: !kick <clid> [reason]",
+		"1:6 Error: invalid arguments: argument 1 must be a number",
+		"1:6 Error: invalid arguments: unterminated quote",
+		"1:5 Error: boom",
+	}
+	assert.Equal(t, expected, srv.sent)
+}
+
+func TestRouterOptions(t *testing.T) {
+	var errs []error
+	srv := &fakeServer{}
+	r := newRouter(t, srv, Prefix("."), Errors(func(req *Request, err error) {
+		errs = append(errs, err)
+	}))
+
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", "!echo x"))
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", ".echo y"))
+	r.Dispatch(message(ts3.TargetClient, 5, "guest", ".kick"))
+	assert.Equal(t, []string{"1:5 y"}, srv.sent)
+	require.Len(t, errs, 1)
+
+	_, err := New(srv, nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+
+	_, err = New(srv, Prefix(""))
+	assert.Error(t, err)
+}
+
+func TestRouterHandle(t *testing.T) {
+	r := newRouter(t, &fakeServer{})
+
+	err := r.HandleFunc("K", func(*Request) error { return nil })
+	assert.True(t, errors.Is(err, ErrDuplicateCommand))
+
+	err = r.Handle(&Command{Name: "nohandler"})
+	assert.True(t, errors.Is(err, ErrInvalidCommand))
+
+	var names []string
+	for _, c := range r.Commands() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"echo", "fail", "kick"}, names)
+}
+
+func TestRouterRun(t *testing.T) {
+	srv := &fakeServer{}
+	r := newRouter(t, srv)
+
+	ch := make(chan ts3.Notification, 2)
+	ch <- message(ts3.TargetClient, 1, "self", "!echo loop")
+	ch <- message(ts3.TargetClient, 5, "guest", "!echo hi")
+	close(ch)
+	assert.NoError(t, r.Run(context.Background(), ch))
+	assert.Equal(t, []string{"1:5 hi"}, srv.sent)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := r.Run(ctx, make(chan ts3.Notification))
+	assert.True(t, errors.Is(err, context.Canceled))
+}
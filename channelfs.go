@@ -0,0 +1,239 @@
+package ts3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+// ChannelFS is a read-only fs.FS of the files of a channel.
+// Files are downloaded when first read.
+type ChannelFS struct {
+	s   *ServerMethods
+	cid int
+	cpw string
+}
+
+// ChannelFS returns an fs.FS for the files of the channel cid.
+// The channel password cpw is only needed if the channel has a password.
+func (s *ServerMethods) ChannelFS(cid int, cpw string) *ChannelFS {
+	return &ChannelFS{s: s, cid: cid, cpw: cpw}
+}
+
+// Open implements fs.FS.
+func (cfs *ChannelFS) Open(name string) (fs.File, error) {
+	fi, err := cfs.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		return &channelDir{cfs: cfs, name: name, info: fi}, nil
+	}
+
+	return &channelFile{cfs: cfs, info: fi}, nil
+}
+
+// Stat implements fs.StatFS.
+func (cfs *ChannelFS) Stat(name string) (fs.FileInfo, error) {
+	return cfs.stat("stat", name)
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (cfs *ChannelFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entries, err := cfs.s.FTGetFileList(cfs.cid, cfs.cpw, channelPath(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fsError(err)}
+	}
+
+	dirs := make([]fs.DirEntry, len(entries))
+	for i, e := range entries {
+		dirs[i] = &fileInfo{e}
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].Name() < dirs[j].Name()
+	})
+
+	return dirs, nil
+}
+
+// stat returns the fs.FileInfo for name by listing its parent directory.
+func (cfs *ChannelFS) stat(op, name string) (*fileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return &fileInfo{&FileEntry{ChannelID: cfs.cid, Path: "/", Name: ".", IsDir: true}}, nil
+	}
+
+	dir, base := path.Split(name)
+	entries, err := cfs.s.FTGetFileList(cfs.cid, cfs.cpw, channelPath(path.Clean(dir)))
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fsError(err)}
+	}
+
+	for _, e := range entries {
+		if e.Name == base {
+			return &fileInfo{e}, nil
+		}
+	}
+
+	return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// channelPath returns the channel file path for the fs.FS name.
+func channelPath(name string) string {
+	if name == "." || name == "" {
+		return "/"
+	}
+	return "/" + name
+}
+
+// fsError returns fs.ErrNotExist if err indicates a file wasn't found, otherwise err.
+func fsError(err error) error {
+	if errors.Is(err, ErrFileNotFound) {
+		return fs.ErrNotExist
+	}
+	return err
+}
+
+// fileInfo implements fs.FileInfo and fs.DirEntry for a FileEntry.
+type fileInfo struct {
+	e *FileEntry
+}
+
+// Name implements fs.FileInfo and fs.DirEntry.
+func (fi *fileInfo) Name() string { return fi.e.Name }
+
+// Size implements fs.FileInfo.
+func (fi *fileInfo) Size() int64 { return fi.e.Size }
+
+// Mode implements fs.FileInfo.
+func (fi *fileInfo) Mode() fs.FileMode {
+	if fi.e.IsDir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// ModTime implements fs.FileInfo.
+func (fi *fileInfo) ModTime() time.Time { return fi.e.ModTime }
+
+// IsDir implements fs.FileInfo and fs.DirEntry.
+func (fi *fileInfo) IsDir() bool { return fi.e.IsDir }
+
+// Sys implements fs.FileInfo, it returns the *FileEntry.
+func (fi *fileInfo) Sys() interface{} { return fi.e }
+
+// Type implements fs.DirEntry.
+func (fi *fileInfo) Type() fs.FileMode { return fi.Mode().Type() }
+
+// Info implements fs.DirEntry.
+func (fi *fileInfo) Info() (fs.FileInfo, error) { return fi, nil }
+
+// channelFile is a file opened by ChannelFS.
+type channelFile struct {
+	cfs    *ChannelFS
+	info   *fileInfo
+	rc     io.ReadCloser
+	closed bool
+}
+
+// Stat implements fs.File.
+func (f *channelFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// Read implements fs.File, starting the download on first use.
+func (f *channelFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.info.e.Path, Err: fs.ErrClosed}
+	}
+
+	if f.rc == nil {
+		rc, err := f.cfs.s.Download(context.Background(), f.cfs.cid, f.info.e.Path, &TransferOptions{ChannelPassword: f.cfs.cpw})
+		if err != nil {
+			return 0, &fs.PathError{Op: "read", Path: f.info.e.Path, Err: fsError(err)}
+		}
+		f.rc = rc
+	}
+
+	return f.rc.Read(p)
+}
+
+// Close implements fs.File.
+func (f *channelFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.info.e.Path, Err: fs.ErrClosed}
+	}
+	f.closed = true
+
+	if f.rc != nil {
+		return f.rc.Close()
+	}
+	return nil
+}
+
+// channelDir is a directory opened by ChannelFS.
+type channelDir struct {
+	cfs     *ChannelFS
+	name    string
+	info    *fileInfo
+	entries []fs.DirEntry // entries is nil until the first ReadDir.
+	offset  int
+}
+
+// Stat implements fs.File.
+func (d *channelDir) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Read implements fs.File.
+func (d *channelDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *channelDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.entries == nil {
+		entries, err := d.cfs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		if entries == nil {
+			entries = []fs.DirEntry{}
+		}
+		d.entries = entries
+	}
+
+	rem := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rem, nil
+	}
+
+	if len(rem) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(rem) {
+		n = len(rem)
+	}
+	d.offset += n
+
+	return rem[:n], nil
+}
+
+// Close implements fs.File.
+func (d *channelDir) Close() error {
+	return nil
+}
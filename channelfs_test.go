@@ -0,0 +1,85 @@
+package ts3
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelFS(t *testing.T) {
+	ft := newFTServer(t, map[string][]byte{
+		"readme00": []byte("hello world"),
+		"docs/a00": []byte("aaaaa"),
+	})
+	defer func() {
+		assert.NoError(t, ft.Close())
+	}()
+
+	s := newServer(t,
+		handler("ftgetfilelist", func(line string) string {
+			switch lineArgs(line)["path"] {
+			case "/":
+				return `cid=5 path=\/ name=docs size=0 datetime=1691527133 type=0|name=readme.txt size=11 datetime=1691527200 type=1|name=empty size=0 datetime=1691527200 type=0`
+			case "/docs":
+				return `cid=5 path=\/docs name=a.txt size=5 datetime=1691527300 type=1`
+			case "/empty":
+				return `error id=1281 msg=database\sempty\sresult\sset`
+			default:
+				return `error id=2051 msg=file\snot\sfound`
+			}
+		}),
+		handler("ftinitdownload", func(line string) string {
+			keys := map[string]string{"/readme.txt": "readme00", "/docs/a.txt": "docs/a00"}
+			args := lineArgs(line)
+			return fmt.Sprintf("clientftfid=%s serverftfid=7 ftkey=%s port=%d size=%d",
+				args["clientftfid"], keys[args["name"]], ft.Port(), len(ft.downloads[keys[args["name"]]]))
+		}),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	cfs := c.Server.ChannelFS(5, "")
+	require.NoError(t, fstest.TestFS(cfs, "readme.txt", "docs/a.txt", "empty"))
+
+	var walked []string
+	err = fs.WalkDir(cfs, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, path)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{".", "docs", "docs/a.txt", "empty", "readme.txt"}, walked)
+
+	data, err := fs.ReadFile(cfs, "docs/a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "aaaaa", string(data))
+
+	fi, err := fs.Stat(cfs, "readme.txt")
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), fi.Size())
+	assert.Equal(t, time.Unix(1691527200, 0), fi.ModTime())
+	assert.Equal(t, "/readme.txt", fi.Sys().(*FileEntry).Path)
+
+	_, err = cfs.Open("missing.txt")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = cfs.ReadDir("missing")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = cfs.Open("../escape")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
@@ -2,6 +2,7 @@ package ts3
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"net"
@@ -26,6 +27,10 @@ const (
 	// startBufSize is the initial size of allocation for the parse buffer.
 	startBufSize = 4096
 
+	// streamChunkSize is the size at which a line of a streamed response is
+	// passed on before the end of the line has been received.
+	streamChunkSize = 64 << 10
+
 	// responseErrTimeout is the timeout used for sending response errors.
 	responseErrTimeout = time.Millisecond * 100
 )
@@ -57,6 +62,15 @@ type Connection interface {
 	Connect(addr string, timeout time.Duration) error
 }
 
+// Invoker executes cmd and returns the raw response lines.
+type Invoker func(ctx context.Context, cmd *Cmd) ([]string, error)
+
+// Interceptor intercepts the execution of cmd. It can inspect or modify cmd,
+// call next to continue the chain and inspect or replace the result. Not
+// calling next prevents cmd being sent to the server, which can be used to
+// return cached or fake results.
+type Interceptor func(ctx context.Context, cmd *Cmd, next Invoker) ([]string, error)
+
 type response struct {
 	err   error
 	lines []string
@@ -73,12 +87,23 @@ type Client struct {
 	notifyBufSize int
 	work          chan string
 	response      chan response
+	execSlot      chan struct{} // execSlot serializes commands so each receives its own response.
+	streamMtx     sync.Mutex
+	stream        io.Writer // stream receives the response data of the pending command, if streamed.
+	partial       bool      // partial is true if the last line scanned is incomplete, only accessed while scanning.
 	notify        chan Notification
 	closing       chan struct{} // closing is closed to indicate we're closing our connection.
 	done          chan struct{} // done is closed once we're seen a fatal error.
 	doneOnce      sync.Once
 	connectHeader string
 	wg            sync.WaitGroup
+	permsMtx      sync.Mutex
+	perms         *PermissionCatalog // perms is loaded on demand by ServerMethods.Permissions.
+	ftHost        string
+	ftID          uint32 // ftID is the last client file transfer ID, accessed atomically.
+	interceptors  []Interceptor
+	invoker       Invoker     // invoker executes commands through the interceptors.
+	wire          *wireLogger // wire logs the lines sent and received, nil if disabled.
 
 	Server *ServerMethods
 }
@@ -91,6 +116,21 @@ func Timeout(timeout time.Duration) func(*Client) error {
 	}
 }
 
+// Interceptors adds interceptors which are called for each command executed
+// by the client. The first interceptor is the outermost, so it sees the
+// command first and the result last.
+func Interceptors(interceptors ...Interceptor) func(*Client) error {
+	return func(c *Client) error {
+		for _, f := range interceptors {
+			if f == nil {
+				return ErrNilOption
+			}
+		}
+		c.interceptors = append(c.interceptors, interceptors...)
+		return nil
+	}
+}
+
 // KeepAlive sets the keepAlive interval.
 func KeepAlive(keepAlive time.Duration) func(*Client) error {
 	return func(c *Client) error {
@@ -164,6 +204,7 @@ func NewClient(addr string, options ...func(c *Client) error) (*Client, error) {
 		notifyBufSize: DefaultNotifyBufSize,
 		work:          make(chan string),
 		response:      make(chan response),
+		execSlot:      make(chan struct{}, 1),
 		closing:       make(chan struct{}),
 		done:          make(chan struct{}),
 		connectHeader: DefaultConnectHeader,
@@ -179,6 +220,15 @@ func NewClient(addr string, options ...func(c *Client) error) (*Client, error) {
 
 	c.notify = make(chan Notification, c.notifyBufSize)
 
+	// Chain interceptors so the first is the outermost.
+	c.invoker = c.exec
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		f, next := c.interceptors[i], c.invoker
+		c.invoker = func(ctx context.Context, cmd *Cmd) ([]string, error) {
+			return f(ctx, cmd, next)
+		}
+	}
+
 	// Wire up command groups
 	c.Server = &ServerMethods{Client: c}
 
@@ -188,7 +238,7 @@ func NewClient(addr string, options ...func(c *Client) error) (*Client, error) {
 
 	c.scanner = bufio.NewScanner(bufio.NewReader(c.conn))
 	c.scanner.Buffer(c.buf, c.maxBufSize)
-	c.scanner.Split(ScanLines)
+	c.scanner.Split(c.scanLines)
 
 	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
 		return nil, fmt.Errorf("client: set deadline: %w", err)
@@ -249,10 +299,25 @@ func (c *Client) messageHandler() {
 	}()
 
 	buf := make([]string, 0, 10)
+	var cont, discard bool // cont is true if the line continues a partial line.
 	for {
 		if c.scanner.Scan() {
 			line := c.scanner.Text()
+			if cont || c.partial {
+				// Part of a line too long to parse, only response data is streamed.
+				if !cont {
+					discard = strings.HasPrefix(line, "error ") || strings.HasPrefix(line, "notify")
+				}
+				if !discard {
+					c.wire.recv(line, false)
+					c.streamData(line, !c.partial)
+				}
+				cont = c.partial
+				continue
+			}
+
 			if line == "error id=0 msg=ok" {
+				c.wire.recv(line, true)
 				var resp response
 				// Avoid creating a new buf if there was no data in the response.
 				if len(buf) > 0 {
@@ -261,12 +326,14 @@ func (c *Client) messageHandler() {
 				}
 				c.response <- resp
 			} else if matches := respTrailerRe.FindStringSubmatch(line); len(matches) == 4 {
+				c.wire.recv(line, true)
 				c.response <- response{err: NewError(matches)}
 				// Avoid creating a new buf if there was no data in the response.
 				if len(buf) > 0 {
 					buf = make([]string, 0, 10)
 				}
 			} else if strings.Index(line, "notify") == 0 {
+				c.wire.notify(line)
 				if n, err := decodeNotification(line); err == nil {
 					// non-blocking write
 					select {
@@ -276,7 +343,10 @@ func (c *Client) messageHandler() {
 				}
 			} else {
 				// Partial response.
-				buf = append(buf, line)
+				c.wire.recv(line, false)
+				if !c.streamData(line, true) {
+					buf = append(buf, line)
+				}
 			}
 		} else {
 			if err := c.scanErr(); c.fatalError(err) {
@@ -290,6 +360,68 @@ func (c *Client) messageHandler() {
 	}
 }
 
+// scanLines is the split function of the scanner. It's ScanLines except
+// that while a response is being streamed long lines are returned in parts,
+// setting c.partial, instead of being limited by the parse buffer.
+func (c *Client) scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := ScanLines(data, atEOF)
+	if advance != 0 || token != nil || err != nil {
+		c.partial = false
+		return advance, token, err
+	}
+
+	size := streamChunkSize
+	if c.maxBufSize < size {
+		size = c.maxBufSize
+	}
+	if len(data) < size || (!c.partial && c.streamWriter() == nil) {
+		// Request more data.
+		return 0, nil, nil
+	}
+
+	// Keep a trailing new line as it may be the start of the end-of-line marker.
+	n := len(data)
+	if data[n-1] == '\n' {
+		n--
+	}
+	c.partial = true
+
+	return n, data[:n], nil
+}
+
+// streamWriter returns the writer of the pending streamed command, nil if none.
+func (c *Client) streamWriter() io.Writer {
+	c.streamMtx.Lock()
+	defer c.streamMtx.Unlock()
+
+	return c.stream
+}
+
+// setStream sets the writer of the pending streamed command.
+func (c *Client) setStream(w io.Writer) {
+	c.streamMtx.Lock()
+	defer c.streamMtx.Unlock()
+
+	c.stream = w
+}
+
+// streamData writes data, followed by a new line if eol is true, to the writer
+// of the pending streamed command. It returns false if no command is streamed.
+// Write errors are left to the writer to report.
+func (c *Client) streamData(data string, eol bool) bool {
+	w := c.streamWriter()
+	if w == nil {
+		return false
+	}
+
+	if eol {
+		data += "\n"
+	}
+	w.Write([]byte(data)) //nolint: errcheck
+
+	return true
+}
+
 // responseErr sends err to c.response with a timeout to ensure it
 // doesn't block forever when multiple errors occur during the
 // processing of a single ExecCmd call.
@@ -310,6 +442,7 @@ func (c *Client) workHandler() {
 	for {
 		select {
 		case w := <-c.work:
+			c.wire.send(w)
 			if err := c.write([]byte(w)); c.fatalError(err) {
 				// Command send failed, inform the caller.
 				c.responseErr(err)
@@ -317,6 +450,7 @@ func (c *Client) workHandler() {
 			}
 		case <-time.After(c.keepAlive):
 			// Send a keep alive to prevent the connection from timing out.
+			c.wire.keepAlive()
 			if err := c.write(keepAliveData); c.fatalError(err) {
 				// We don't send to c.response as no ExecCmd is expecting a
 				// response and the next caller will get an error.
@@ -347,30 +481,104 @@ func (c *Client) Exec(cmd string) ([]string, error) {
 }
 
 // ExecCmd executes cmd on the server and returns the response.
+//
+// Commands are executed one at a time, as responses aren't tagged with the
+// command which caused them, so concurrent calls on the same Client wait for
+// the commands before them to complete. The timeout applies once cmd is sent.
 func (c *Client) ExecCmd(cmd *Cmd) ([]string, error) {
+	return c.ExecCmdContext(context.Background(), cmd)
+}
+
+// ExecCmdContext executes cmd on the server through the interceptors and
+// returns the response. If cmd has a response set the lines returned by the
+// interceptors are decoded into it.
+//
+// If ctx is done while waiting for the response ctx.Err() is returned and,
+// as with ErrTimeout, the response may be returned to a later command.
+func (c *Client) ExecCmdContext(ctx context.Context, cmd *Cmd) ([]string, error) {
+	lines, err := c.invoker(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.response != nil && cmd.stream == nil {
+		if err := DecodeResponse(lines, cmd.response); err != nil {
+			return nil, err
+		}
+	}
+
+	return lines, nil
+}
+
+// exec sends cmd to the server and returns the response lines.
+// Concurrent calls are serialized as responses aren't tagged with the
+// command which caused them.
+func (c *Client) exec(ctx context.Context, cmd *Cmd) ([]string, error) {
 	select {
-	case c.work <- cmd.String():
+	case c.execSlot <- struct{}{}:
+		defer func() { <-c.execSlot }()
 	case <-c.done:
 		return nil, ErrNotConnected
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var progress chan struct{}
+	if cmd.stream != nil {
+		progress = make(chan struct{}, 1)
+		c.setStream(&activityWriter{w: cmd.stream, active: progress})
+		defer c.setStream(nil)
 	}
 
-	var resp response
 	select {
-	case resp = <-c.response:
-		if resp.err != nil {
-			return nil, resp.err
-		}
-	case <-time.After(c.timeout):
-		return nil, ErrTimeout
+	case c.work <- cmd.String():
+	case <-c.done:
+		return nil, ErrNotConnected
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 
-	if cmd.response != nil {
-		if err := DecodeResponse(resp.lines, cmd.response); err != nil {
-			return nil, err
+	t := time.NewTimer(c.timeout)
+	defer t.Stop()
+
+	for {
+		select {
+		case resp := <-c.response:
+			if resp.err != nil {
+				return nil, resp.err
+			}
+			return resp.lines, nil
+		case <-progress:
+			// Streamed data is being received, restart the timeout.
+			if !t.Stop() {
+				select {
+				case <-t.C:
+				default:
+				}
+			}
+			t.Reset(c.timeout)
+		case <-t.C:
+			return nil, ErrTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
 	}
+}
+
+// activityWriter is an io.Writer which signals active for each write.
+type activityWriter struct {
+	w      io.Writer
+	active chan struct{}
+}
+
+// Write implements io.Writer.
+func (w *activityWriter) Write(p []byte) (int, error) {
+	select {
+	case w.active <- struct{}{}:
+	default:
+	}
 
-	return resp.lines, nil
+	return w.w.Write(p)
 }
 
 // IsConnected returns true if the client is connected,
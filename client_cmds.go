@@ -0,0 +1,52 @@
+package ts3
+
+// KickReason is the reason ID used when kicking a client.
+type KickReason int
+
+const (
+	// KickFromChannel kicks the client from its channel into the default channel.
+	KickFromChannel KickReason = 4
+
+	// KickFromServer kicks the client from the server.
+	KickFromServer KickReason = 5
+)
+
+// ClientKick kicks the clients clids from their channel or the server, depending on reason,
+// displaying msg to them.
+func (s *ServerMethods) ClientKick(reason KickReason, msg string, clids ...int) error {
+	args := []CmdArg{clidArgs(clids), NewArg("reasonid", int(reason))}
+	if msg != "" {
+		args = append(args, NewArg("reasonmsg", msg))
+	}
+	_, err := s.ExecCmd(NewCmd("clientkick").WithArgs(args...))
+	return err
+}
+
+// ClientPoke sends a poke message to the client clid.
+func (s *ServerMethods) ClientPoke(clid int, msg string) error {
+	_, err := s.ExecCmd(NewCmd("clientpoke").WithArgs(
+		NewArg("clid", clid),
+		NewArg("msg", msg),
+	))
+	return err
+}
+
+// ClientMove moves the clients clids to the channel cid.
+// The channel password cpw is only needed if the channel has a password.
+func (s *ServerMethods) ClientMove(cid int, cpw string, clids ...int) error {
+	args := []CmdArg{clidArgs(clids), NewArg("cid", cid)}
+	if cpw != "" {
+		args = append(args, NewArg("cpw", cpw))
+	}
+	_, err := s.ExecCmd(NewCmd("clientmove").WithArgs(args...))
+	return err
+}
+
+// clidArgs returns an ArgGroup of clid arguments.
+func clidArgs(clids []int) *ArgGroup {
+	args := make([]CmdArg, len(clids))
+	for i, id := range clids {
+		args[i] = NewArg("clid", id)
+	}
+	return NewArgGroup(args...)
+}
@@ -0,0 +1,58 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsClient(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	clientkick := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ClientKick(KickFromServer, "bye", 42087))
+		assert.NoError(t, c.Server.ClientKick(KickFromChannel, "", 42087, 42088))
+	}
+
+	clientpoke := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ClientPoke(42087, "wake up"))
+	}
+
+	clientmove := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ClientMove(39, "", 42087))
+		assert.NoError(t, c.Server.ClientMove(39, "secret", 42087, 42088))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"clientkick", clientkick},
+		{"clientpoke", clientpoke},
+		{"clientmove", clientmove},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestClidArgs(t *testing.T) {
+	assert.Equal(t, "clid=1|clid=2", clidArgs([]int{1, 2}).ArgString())
+}
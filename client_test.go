@@ -1,12 +1,17 @@
 package ts3
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient(t *testing.T) {
@@ -302,3 +307,114 @@ func TestClientBadHeader(t *testing.T) {
 	// Should never get here
 	assert.NoError(t, c.Close())
 }
+
+func TestClientInterceptors(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var calls []string
+	trace := func(name string) Interceptor {
+		return func(ctx context.Context, cmd *Cmd, next Invoker) ([]string, error) {
+			calls = append(calls, name+" "+cmd.Name())
+			lines, err := next(ctx, cmd)
+			calls = append(calls, fmt.Sprintf("%v %v %v", name, len(lines), err))
+			return lines, err
+		}
+	}
+	fake := func(ctx context.Context, cmd *Cmd, next Invoker) ([]string, error) {
+		if cmd.Name() == "fake" {
+			return []string{"version=1.2.3 build=1 platform=Fake"}, nil
+		}
+		return next(ctx, cmd)
+	}
+
+	_, err := NewClient(s.Addr, Interceptors(fake, nil))
+	assert.Equal(t, ErrNilOption, err)
+
+	c, err := NewClient(s.Addr, Timeout(time.Second), Interceptors(trace("outer"), trace("inner")), Interceptors(fake))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	v := &Version{}
+	_, err = c.ExecCmd(NewCmd("version").WithResponse(v))
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.12.2", v.Version)
+
+	_, err = c.Exec("invalid")
+	assert.Error(t, err)
+
+	// The fake interceptor handles the command without the server.
+	_, err = c.ExecCmd(NewCmd("fake").WithResponse(v))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.3", v.Version)
+
+	assert.Equal(t, []string{
+		"outer version",
+		"inner version",
+		"inner 1 <nil>",
+		"outer 1 <nil>",
+		"outer invalid",
+		"inner invalid",
+		"inner 0 command not found (256)",
+		"outer 0 command not found (256)",
+		"outer fake",
+		"inner fake",
+		"inner 1 <nil>",
+		"outer 1 <nil>",
+	}, calls)
+}
+
+func TestClientExecCmdContext(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.ExecCmdContext(ctx, NewCmd("version"))
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	// Not receiving a response is interrupted by the context.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.ExecCmdContext(ctx, NewCmd(" "))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestClientStream(t *testing.T) {
+	long := strings.Repeat("x", 5000)
+	s := newServer(t, handler("long", func(string) string {
+		return long + "\n\rshort"
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	// Lines longer than the parse buffer are streamed.
+	c, err := NewClient(s.Addr, Timeout(time.Second), Buffer(make([]byte, 256), 512))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	var buf bytes.Buffer
+	lines, err := c.ExecCmd(NewCmd("long").WithStream(&buf))
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+	assert.Equal(t, long+"\nshort\n", buf.String())
+
+	lines, err = c.Exec("version")
+	require.NoError(t, err)
+	assert.Equal(t, []string{commands["version"]}, lines)
+}
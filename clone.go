@@ -0,0 +1,207 @@
+package ts3
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// AdminGroupName is the name of the server group granted by the administrator
+// privilege key created by Clone, unless CloneOptions.AdminGroupID is set.
+const AdminGroupName = "Server Admin"
+
+// CloneOptions controls how Clone copies a virtual server.
+type CloneOptions struct {
+	// ServerID is the ID of the virtual server to clone on the source instance.
+	ServerID int
+
+	// TargetID is the ID of an existing virtual server on the destination
+	// instance to deploy to. If zero a new virtual server is created.
+	TargetID int
+
+	// Name overrides the name of the cloned server.
+	Name string
+
+	// Port overrides the UDP port of the cloned server. If zero a created
+	// server uses the first unused port and an existing server keeps its port.
+	Port uint16
+
+	// Password is used to encrypt the snapshot while it's transferred.
+	// If empty a random password is used.
+	Password string
+
+	// KeepFiles keeps the existing files of the target virtual server.
+	KeepFiles bool
+
+	// KeepKeys keeps the privilege keys copied from the source server,
+	// by default they are deleted so they can't be used on the clone.
+	KeepKeys bool
+
+	// AdminGroupID is the server group granted by the administrator privilege
+	// key. If zero the regular server group named AdminGroupName is used and
+	// no key is created if there is no such group.
+	AdminGroupID int
+
+	// Overrides are additional virtual server properties applied after the
+	// snapshot has been deployed.
+	Overrides []CmdArg
+}
+
+// CloneResult is the result of Clone.
+type CloneResult struct {
+	// ServerID is the ID of the cloned virtual server on the destination instance.
+	ServerID int
+
+	// Port is the UDP port of the cloned virtual server.
+	Port uint16
+
+	// Token is a privilege key for the administrator group of the cloned server,
+	// empty if no administrator group was found.
+	Token string
+
+	// Channels maps the channel IDs of the source server to the channel IDs of
+	// the cloned server.
+	Channels map[int]int
+}
+
+// Clone copies the virtual server opts.ServerID from the src instance to the dst
+// instance using a snapshot, then applies the overrides in opts and creates an
+// administrator privilege key. Both clients must be logged in with sufficient
+// permissions, the selected virtual server of each is changed.
+//
+// If Clone created the target server and a later step fails the server is deleted.
+func Clone(src, dst *Client, opts *CloneOptions) (res *CloneResult, err error) {
+	if opts == nil || opts.ServerID == 0 {
+		return nil, ErrCloneNoSource
+	}
+
+	password := opts.Password
+	if password == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("clone: password: %w", err)
+		}
+		password = hex.EncodeToString(b)
+	}
+
+	if err := src.Use(opts.ServerID); err != nil {
+		return nil, fmt.Errorf("clone: use source %d: %w", opts.ServerID, err)
+	}
+
+	info, err := src.Server.Info()
+	if err != nil {
+		return nil, fmt.Errorf("clone: source info: %w", err)
+	}
+
+	snap, err := src.Server.snapshotFile(password)
+	if err != nil {
+		return nil, fmt.Errorf("clone: snapshot: %w", err)
+	}
+
+	res = &CloneResult{ServerID: opts.TargetID}
+	name := opts.Name
+	if name == "" {
+		name = info.Name
+	}
+
+	if opts.TargetID == 0 {
+		var args []CmdArg
+		if opts.Port != 0 {
+			args = append(args, NewArg("virtualserver_port", opts.Port))
+		}
+
+		created, cerr := dst.Server.Create(name, args...)
+		if cerr != nil {
+			return nil, fmt.Errorf("clone: create: %w", cerr)
+		}
+		res.ServerID = created.ID
+		res.Port = created.Port
+
+		defer func() {
+			if err != nil {
+				// Best effort, the original error is more useful.
+				dst.Server.Stop(created.ID)   //nolint: errcheck
+				dst.Server.Delete(created.ID) //nolint: errcheck
+			}
+		}()
+	}
+
+	if err := dst.Use(res.ServerID); err != nil {
+		return nil, fmt.Errorf("clone: use target %d: %w", res.ServerID, err)
+	}
+
+	deployed, err := dst.Server.SnapshotDeployFile(snap, password, &DeployOptions{
+		KeepFiles: opts.KeepFiles,
+		Mapping:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("clone: deploy: %w", err)
+	}
+	res.Channels = deployed.Channels
+
+	// The snapshot contains the source name, restore the requested one.
+	args := []CmdArg{NewArg("virtualserver_name", name)}
+	if port := opts.Port; port != 0 || res.Port != 0 {
+		if port == 0 {
+			port = res.Port
+		}
+		args = append(args, NewArg("virtualserver_port", port))
+	}
+	args = append(args, opts.Overrides...)
+	if err := dst.Server.Edit(args...); err != nil {
+		return nil, fmt.Errorf("clone: edit: %w", err)
+	}
+
+	if !opts.KeepKeys {
+		keys, err := dst.Server.PrivilegeKeyList()
+		if err != nil && !isEmptyResult(err) {
+			return nil, fmt.Errorf("clone: list keys: %w", err)
+		}
+		for _, k := range keys {
+			if err := dst.Server.PrivilegeKeyDelete(k.Token); err != nil {
+				return nil, fmt.Errorf("clone: delete key: %w", err)
+			}
+		}
+	}
+
+	if res.Token, err = cloneAdminToken(dst, opts.AdminGroupID); err != nil {
+		return nil, err
+	}
+
+	target, err := dst.Server.Info()
+	if err != nil {
+		return nil, fmt.Errorf("clone: target info: %w", err)
+	}
+	res.Port = uint16(target.Port)
+
+	return res, nil
+}
+
+// cloneAdminToken creates a privilege key for the administrator group sgid on the
+// selected server of c. If sgid is zero the group named AdminGroupName is used.
+func cloneAdminToken(c *Client, sgid int) (string, error) {
+	if sgid == 0 {
+		groups, err := c.Server.GroupList()
+		if err != nil {
+			return "", fmt.Errorf("clone: list groups: %w", err)
+		}
+
+		for _, g := range groups {
+			if g.Type == 1 && g.Name == AdminGroupName {
+				sgid = g.ID
+				break
+			}
+		}
+
+		if sgid == 0 {
+			return "", nil
+		}
+	}
+
+	token, err := c.Server.PrivilegeKeyCreate(NewServerGroupToken(sgid))
+	if err != nil {
+		return "", fmt.Errorf("clone: admin token: %w", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,157 @@
+package ts3
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	var lines []string
+	record := func(resp string) func(line string) string {
+		return func(line string) string {
+			lines = append(lines, line)
+			return resp
+		}
+	}
+	deploy := `sid=2|ocid=1 ncid=5|ocid=2 ncid=6`
+	s := newServer(t,
+		handler("use", record("")),
+		handler("serverinfo", record(`virtualserver_name=Test\sServer virtualserver_port=9988`)),
+		handler("serversnapshotcreate", record(`version=3 data=KLUv\/aTFeAEAjeAA salt=c2FsdA==`)),
+		handler("servercreate", record(`sid=2 virtualserver_port=9988 token=eKnFZQ9EK7G7MhtuQB6+N2B1PNZZ6OZL3ycDp2OW`)),
+		handler("serversnapshotdeploy", func(line string) string {
+			lines = append(lines, line)
+			return deploy
+		}),
+		handler("serveredit", record("")),
+		handler("privilegekeylist", record(`token=old token_type=0 token_id1=6 token_id2=0 token_created=1499948005 token_description`)),
+		handler("privilegekeydelete", record("")),
+		handler("servergrouplist", record(`sgid=2 name=Admin\sServer\sQuery type=2|sgid=6 name=Server\sAdmin type=1`)),
+		handler("privilegekeyadd", record(`token=newtoken`)),
+		handler("serverstop", record("")),
+		handler("serverdelete", record("")),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	src, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, src.Close())
+	}()
+
+	dst, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, dst.Close())
+	}()
+
+	// filter returns the recorded commands, dropping the snapshot data.
+	filter := func() []string {
+		var cmds []string
+		for _, l := range lines {
+			if strings.HasPrefix(l, "serversnapshotdeploy") {
+				l = l[:strings.Index(l, " salt=")]
+			}
+			cmds = append(cmds, l)
+		}
+		lines = nil
+		return cmds
+	}
+
+	create := func(t *testing.T) {
+		res, err := Clone(src, dst, &CloneOptions{
+			ServerID:  1,
+			Password:  "secret",
+			Overrides: []CmdArg{NewArg("virtualserver_maxclients", 10)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &CloneResult{
+			ServerID: 2,
+			Port:     9988,
+			Token:    "newtoken",
+			Channels: map[int]int{1: 5, 2: 6},
+		}, res)
+		assert.Equal(t, []string{
+			"use sid=1",
+			"serverinfo",
+			"serversnapshotcreate password=secret",
+			`servercreate virtualserver_name=Test\sServer`,
+			"use sid=2",
+			"serversnapshotdeploy password=secret version=3",
+			`serveredit virtualserver_name=Test\sServer virtualserver_port=9988 virtualserver_maxclients=10`,
+			"privilegekeylist",
+			"privilegekeydelete token=old",
+			"servergrouplist",
+			"privilegekeyadd tokentype=0 tokenid1=6 tokenid2=0",
+			"serverinfo",
+		}, filter())
+	}
+
+	existing := func(t *testing.T) {
+		res, err := Clone(src, dst, &CloneOptions{
+			ServerID:     1,
+			TargetID:     3,
+			Name:         "Copy",
+			Password:     "secret",
+			KeepFiles:    true,
+			KeepKeys:     true,
+			AdminGroupID: 7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "newtoken", res.Token)
+		assert.Equal(t, 3, res.ServerID)
+		assert.Equal(t, []string{
+			"use sid=1",
+			"serverinfo",
+			"serversnapshotcreate password=secret",
+			"use sid=3",
+			"serversnapshotdeploy password=secret version=3",
+			"serveredit virtualserver_name=Copy",
+			"privilegekeyadd tokentype=0 tokenid1=7 tokenid2=0",
+			"serverinfo",
+		}, filter())
+	}
+
+	rollback := func(t *testing.T) {
+		deploy = "error id=2568 msg=insufficient\\sclient\\spermissions failed_permid=4"
+		defer func() {
+			deploy = `sid=2|ocid=1 ncid=5|ocid=2 ncid=6`
+		}()
+
+		_, err := Clone(src, dst, &CloneOptions{ServerID: 1, Port: 9999})
+		assert.Error(t, err)
+		cmds := filter()
+		assert.Contains(t, cmds, `servercreate virtualserver_port=9999 virtualserver_name=Test\sServer`)
+		assert.Equal(t, []string{"serverstop sid=2", "serverdelete sid=2"}, cmds[len(cmds)-2:])
+	}
+
+	invalid := func(t *testing.T) {
+		_, err := Clone(src, dst, nil)
+		assert.Equal(t, ErrCloneNoSource, err)
+		_, err = Clone(src, dst, &CloneOptions{})
+		assert.Equal(t, ErrCloneNoSource, err)
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"create", create},
+		{"existing", existing},
+		{"rollback", rollback},
+		{"invalid", invalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines = nil
+			tc.f(t)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package ts3
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -11,6 +12,7 @@ type Cmd struct {
 	args     []CmdArg
 	options  []string
 	response interface{}
+	stream   io.Writer
 }
 
 // NewCmd creates a new Cmd.
@@ -36,6 +38,36 @@ func (c *Cmd) WithResponse(r interface{}) *Cmd {
 	return c
 }
 
+// WithStream sets a writer which the response data is written to, one line at a time
+// separated by new lines, instead of it being returned. Lines may be written in
+// multiple parts, so they aren't limited by the client parse buffer. A Response set
+// with WithResponse is ignored.
+func (c *Cmd) WithStream(w io.Writer) *Cmd {
+	c.stream = w
+	return c
+}
+
+// Name returns the command name.
+func (c *Cmd) Name() string {
+	return c.cmd
+}
+
+// Args returns the command Args.
+func (c *Cmd) Args() []CmdArg {
+	return c.args
+}
+
+// Options returns the command Options.
+func (c *Cmd) Options() []string {
+	return c.options
+}
+
+// Response returns the command Response, nil if none is set.
+func (c *Cmd) Response() interface{} {
+	return c.response
+}
+
+// String returns the command as sent to the server, including the trailing new line.
 func (c *Cmd) String() string {
 	args := make([]interface{}, 1, len(c.args)+len(c.options)+1)
 	args[0] = c.cmd
@@ -78,3 +78,15 @@ func TestCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestCmdAccessors(t *testing.T) {
+	v := &Version{}
+	arg := NewArg("sid", 1)
+	cmd := NewCmd("serverlist").WithArgs(arg).WithOptions("-uid").WithResponse(v)
+
+	assert.Equal(t, "serverlist", cmd.Name())
+	assert.Equal(t, []CmdArg{arg}, cmd.Args())
+	assert.Equal(t, []string{"-uid"}, cmd.Options())
+	assert.Equal(t, v, cmd.Response())
+	assert.Nil(t, NewCmd("version").Response())
+}
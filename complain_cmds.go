@@ -0,0 +1,58 @@
+package ts3
+
+import (
+	"time"
+)
+
+// Complaint represents a complaint about a client.
+type Complaint struct {
+	TargetDatabaseID int       `ms:"tcldbid"`
+	TargetName       string    `ms:"tname"`
+	FromDatabaseID   int       `ms:"fcldbid"`
+	FromName         string    `ms:"fname"`
+	Message          string    `ms:"message"`
+	Timestamp        time.Time `ms:"timestamp"`
+}
+
+// ComplainList returns the complaints about the client tcldbid on the selected server.
+// If tcldbid is 0 all complaints are returned.
+func (s *ServerMethods) ComplainList(tcldbid int) ([]*Complaint, error) {
+	cmd := NewCmd("complainlist")
+	if tcldbid != 0 {
+		cmd.WithArgs(NewArg("tcldbid", tcldbid))
+	}
+
+	var complaints []*Complaint
+	if _, err := s.ExecCmd(cmd.WithResponse(&complaints)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return complaints, nil
+}
+
+// ComplainAdd submits a complaint about the client tcldbid.
+func (s *ServerMethods) ComplainAdd(tcldbid int, message string) error {
+	_, err := s.ExecCmd(NewCmd("complainadd").WithArgs(
+		NewArg("tcldbid", tcldbid),
+		NewArg("message", message),
+	))
+	return err
+}
+
+// ComplainDel deletes the complaint about the client tcldbid submitted by the client fcldbid.
+func (s *ServerMethods) ComplainDel(tcldbid, fcldbid int) error {
+	_, err := s.ExecCmd(NewCmd("complaindel").WithArgs(
+		NewArg("tcldbid", tcldbid),
+		NewArg("fcldbid", fcldbid),
+	))
+	return err
+}
+
+// ComplainDelAll deletes all complaints about the client tcldbid.
+func (s *ServerMethods) ComplainDelAll(tcldbid int) error {
+	_, err := s.ExecCmd(NewCmd("complaindelall").WithArgs(NewArg("tcldbid", tcldbid)))
+	return err
+}
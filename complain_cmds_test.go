@@ -0,0 +1,93 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsComplain(t *testing.T) {
+	s := newServer(t, handler("complainlist", func(line string) string {
+		if lineArgs(line)["tcldbid"] == "20" {
+			return `error id=1281 msg=database\sempty\sresult\sset`
+		}
+		return commands["complainlist"]
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	complainlist := func(t *testing.T) {
+		t.Helper()
+		expected := []*Complaint{
+			{
+				TargetDatabaseID: 19,
+				TargetName:       "bdeb1337",
+				FromDatabaseID:   7,
+				FromName:         "MuhChy",
+				Message:          "Bad guy",
+				Timestamp:        time.Unix(1259527003, 0),
+			},
+			{
+				TargetDatabaseID: 19,
+				TargetName:       "bdeb1337",
+				FromDatabaseID:   1,
+				FromName:         "serveradmin",
+				Message:          "Spamming",
+				Timestamp:        time.Unix(1259527100, 0),
+			},
+		}
+		for _, tcldbid := range []int{0, 19} {
+			complaints, err := c.Server.ComplainList(tcldbid)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, expected, complaints)
+		}
+
+		complaints, err := c.Server.ComplainList(20)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Nil(t, complaints)
+	}
+
+	complainadd := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ComplainAdd(19, "Bad guy"))
+	}
+
+	complaindel := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ComplainDel(19, 7))
+	}
+
+	complaindelall := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ComplainDelAll(19))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"complainlist", complainlist},
+		{"complainadd", complainadd},
+		{"complaindel", complaindel},
+		{"complaindelall", complaindelall},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
@@ -0,0 +1,162 @@
+package ts3
+
+// EffectivePermission is the result of calculating the value of a permission
+// for a client in a channel.
+type EffectivePermission struct {
+	ID    int
+	Name  string
+	Value int
+
+	// Granted is false if no source assigns the permission.
+	Granted bool
+
+	// Source is the entry which determined Value, nil if not Granted.
+	Source *PermissionOverview
+
+	// SourceName is the name of the group or channel of Source, if known.
+	SourceName string
+
+	// Skipped is true if channel and channel group permissions were ignored
+	// due to the skip flag of a server group or client permission.
+	Skipped bool
+
+	// Entries are all the entries which were considered.
+	Entries []*PermissionOverview
+}
+
+// CalculateEffectivePermission calculates the effective value of a single
+// permission from its overview entries, as returned by PermOverview.
+//
+// Sources are applied in the same order as the server:
+//   - Server groups, the highest value wins unless one or more groups
+//     have the negate flag, in which case the lowest negated value wins.
+//   - Client permissions.
+//   - Channel permissions.
+//   - Channel group permissions.
+//   - Channel client permissions.
+//
+// Each source overrides the previous ones, except that channel and channel
+// group permissions are ignored if the winning server group or client
+// permission has the skip flag set.
+func CalculateEffectivePermission(entries []*PermissionOverview) *EffectivePermission {
+	ep := &EffectivePermission{Entries: entries}
+	if len(entries) > 0 {
+		ep.ID = entries[0].PermID
+		ep.Name = entries[0].Name
+	}
+
+	byType := make(map[PermissionType][]*PermissionOverview)
+	for _, e := range entries {
+		byType[e.Type] = append(byType[e.Type], e)
+	}
+
+	var winner *PermissionOverview
+	var negated bool
+	for _, e := range byType[ServerGroupPermission] {
+		switch {
+		case winner == nil:
+			winner, negated = e, e.Negated
+		case e.Negated && !negated:
+			// Negated groups take precedence over non negated ones.
+			winner, negated = e, true
+		case e.Negated && e.Value < winner.Value:
+			winner = e
+		case !e.Negated && !negated && e.Value > winner.Value:
+			winner = e
+		}
+	}
+
+	if c := byType[ClientPermission]; len(c) > 0 {
+		winner = c[0]
+	}
+
+	skip := winner != nil && winner.Skip
+	for _, t := range []PermissionType{ChannelPermission, ChannelGroupPermission, ChannelClientPermission} {
+		e := byType[t]
+		if len(e) == 0 {
+			continue
+		}
+
+		if skip && t != ChannelClientPermission {
+			ep.Skipped = true
+			continue
+		}
+		winner = e[0]
+	}
+
+	if winner != nil {
+		ep.Granted = true
+		ep.Value = winner.Value
+		ep.Source = winner
+	}
+
+	return ep
+}
+
+// EffectivePermission calculates the effective value of the permission name for
+// the client cldbid in the channel cid, including details of which source won.
+func (s *ServerMethods) EffectivePermission(cid, cldbid int, name string) (*EffectivePermission, error) {
+	entries, err := s.PermOverview(cid, cldbid, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ep := CalculateEffectivePermission(entries)
+	if ep.Name == "" {
+		// Nothing assigned so populate from the catalog.
+		pc, err := s.Permissions()
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := pc.ByName(name); ok {
+			ep.ID, ep.Name = p.ID, p.Name
+		}
+	}
+
+	if ep.Source != nil {
+		if ep.SourceName, err = s.permissionSourceName(ep.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	return ep, nil
+}
+
+// permissionSourceName returns the name of the group or channel which e is assigned to
+// or an empty string if e is a client permission.
+func (s *ServerMethods) permissionSourceName(e *PermissionOverview) (string, error) {
+	switch e.Type {
+	case ServerGroupPermission:
+		groups, err := s.GroupList()
+		if err != nil {
+			return "", err
+		}
+		for _, g := range groups {
+			if g.ID == e.ID1 {
+				return g.Name, nil
+			}
+		}
+	case ChannelGroupPermission:
+		groups, err := s.ChannelGroupList()
+		if err != nil {
+			return "", err
+		}
+		for _, g := range groups {
+			if g.ID == e.ID1 {
+				return g.Name, nil
+			}
+		}
+	case ChannelPermission, ChannelClientPermission:
+		channels, err := s.ChannelList()
+		if err != nil {
+			return "", err
+		}
+		for _, c := range channels {
+			if c.ID == e.ID1 {
+				return c.ChannelName, nil
+			}
+		}
+	}
+
+	return "", nil
+}
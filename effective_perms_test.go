@@ -0,0 +1,158 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateEffectivePermission(t *testing.T) {
+	sg1 := &PermissionOverview{Type: ServerGroupPermission, ID1: 6, PermID: 133, Name: "i_client_talk_power", Value: 50}
+	sg2 := &PermissionOverview{Type: ServerGroupPermission, ID1: 7, PermID: 133, Name: "i_client_talk_power", Value: 70}
+	sgNeg1 := &PermissionOverview{Type: ServerGroupPermission, ID1: 8, PermID: 133, Value: 20, Negated: true}
+	sgNeg2 := &PermissionOverview{Type: ServerGroupPermission, ID1: 9, PermID: 133, Value: 10, Negated: true}
+	sgSkip := &PermissionOverview{Type: ServerGroupPermission, ID1: 10, PermID: 133, Value: 80, Skip: true}
+	client := &PermissionOverview{Type: ClientPermission, ID1: 19, PermID: 133, Value: 60}
+	clientSkip := &PermissionOverview{Type: ClientPermission, ID1: 19, PermID: 133, Value: 60, Skip: true}
+	channel := &PermissionOverview{Type: ChannelPermission, ID1: 39, PermID: 133, Value: 5}
+	channelGroup := &PermissionOverview{Type: ChannelGroupPermission, ID1: 5, ID2: 39, PermID: 133, Value: 15}
+	channelClient := &PermissionOverview{Type: ChannelClientPermission, ID1: 39, ID2: 19, PermID: 133, Value: 25}
+
+	tests := map[string]struct {
+		entries []*PermissionOverview
+		source  *PermissionOverview
+		skipped bool
+	}{
+		"none":                        {},
+		"server-group-highest":        {entries: []*PermissionOverview{sg1, sg2}, source: sg2},
+		"server-group-negated-lowest": {entries: []*PermissionOverview{sg2, sgNeg1, sg1, sgNeg2}, source: sgNeg2},
+		"client-overrides-groups":     {entries: []*PermissionOverview{sg1, sg2, client}, source: client},
+		"channel-overrides-client":    {entries: []*PermissionOverview{sg1, client, channel}, source: channel},
+		"channel-group":               {entries: []*PermissionOverview{sg1, client, channel, channelGroup}, source: channelGroup},
+		"channel-client":              {entries: []*PermissionOverview{sg1, channelGroup, channelClient}, source: channelClient},
+		"server-group-skip":           {entries: []*PermissionOverview{sg1, sgSkip, channelGroup}, source: sgSkip, skipped: true},
+		"client-skip":                 {entries: []*PermissionOverview{sg1, clientSkip, channel, channelGroup}, source: clientSkip, skipped: true},
+		"skip-channel-client":         {entries: []*PermissionOverview{clientSkip, channelGroup, channelClient}, source: channelClient, skipped: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ep := CalculateEffectivePermission(tc.entries)
+			assert.Equal(t, tc.entries, ep.Entries)
+			assert.Equal(t, tc.skipped, ep.Skipped)
+			if tc.source == nil {
+				assert.False(t, ep.Granted)
+				assert.Nil(t, ep.Source)
+				assert.Equal(t, 0, ep.Value)
+				return
+			}
+
+			assert.True(t, ep.Granted)
+			assert.Same(t, tc.source, ep.Source)
+			assert.Equal(t, tc.source.Value, ep.Value)
+			assert.Equal(t, 133, ep.ID)
+		})
+	}
+}
+
+func TestCmdsEffectivePermission(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	channelgrouplist := func(t *testing.T) {
+		t.Helper()
+		groups, err := c.Server.ChannelGroupList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*ChannelGroup{
+			{
+				ID:                5,
+				Name:              "Channel Admin",
+				Type:              1,
+				IconID:            100,
+				Saved:             true,
+				ModifyPower:       75,
+				MemberAddPower:    50,
+				MemberRemovePower: 50,
+			},
+			{
+				ID:                8,
+				Name:              "Guest",
+				Type:              1,
+				Saved:             true,
+				ModifyPower:       75,
+				MemberAddPower:    50,
+				MemberRemovePower: 50,
+			},
+		}
+		assert.Equal(t, expected, groups)
+	}
+
+	effective := func(t *testing.T) {
+		t.Helper()
+		ep, err := c.Server.EffectivePermission(39, 19, "i_client_talk_power")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 133, ep.ID)
+		assert.Equal(t, "i_client_talk_power", ep.Name)
+		assert.True(t, ep.Granted)
+		assert.True(t, ep.Skipped)
+		assert.Equal(t, 60, ep.Value)
+		if assert.NotNil(t, ep.Source) {
+			assert.Equal(t, ClientPermission, ep.Source.Type)
+		}
+		assert.Equal(t, "", ep.SourceName)
+		assert.Len(t, ep.Entries, 3)
+	}
+
+	sourcename := func(t *testing.T) {
+		t.Helper()
+		tests := map[PermissionType]string{
+			ServerGroupPermission:   "Admin Server Query",
+			ChannelGroupPermission:  "Channel Admin",
+			ChannelPermission:       "Default Channel",
+			ChannelClientPermission: "Default Channel",
+			ClientPermission:        "",
+		}
+		ids := map[PermissionType]int{
+			ServerGroupPermission:   2,
+			ChannelGroupPermission:  5,
+			ChannelPermission:       499,
+			ChannelClientPermission: 499,
+			ClientPermission:        19,
+		}
+		for typ, expected := range tests {
+			name, err := c.Server.permissionSourceName(&PermissionOverview{Type: typ, ID1: ids[typ]})
+			if assert.NoError(t, err) {
+				assert.Equal(t, expected, name, typ.String())
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"channelgrouplist", channelgrouplist},
+		{"effective", effective},
+		{"sourcename", sourcename},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
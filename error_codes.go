@@ -0,0 +1,341 @@
+package ts3
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrorCode is a TeamSpeak 3 ServerQuery error ID.
+//
+// ErrorCode implements error so the codes can be used as targets of errors.Is
+// to check the ID of an *Error:
+//
+//	if errors.Is(err, ts3.ErrDatabaseEmptyResult) {
+//		// No results.
+//	}
+type ErrorCode int
+
+// ServerQuery error codes.
+const (
+	ErrOK                     ErrorCode = 0
+	ErrUndefined              ErrorCode = 1
+	ErrNotImplemented         ErrorCode = 2
+	ErrTimeLimitReached       ErrorCode = 5
+	ErrCommandNotFound        ErrorCode = 256
+	ErrUnableToBindPort       ErrorCode = 257
+	ErrNoNetworkPortAvailable ErrorCode = 258
+
+	ErrClientInvalidID            ErrorCode = 512
+	ErrClientNicknameInUse        ErrorCode = 513
+	ErrClientProtocolLimitReached ErrorCode = 515
+	ErrClientInvalidType          ErrorCode = 516
+	ErrClientAlreadySubscribed    ErrorCode = 517
+	ErrClientNotLoggedIn          ErrorCode = 518
+	ErrClientInvalidPassword      ErrorCode = 520
+	ErrClientTooManyClones        ErrorCode = 521
+	ErrClientVersionOutdated      ErrorCode = 522
+	ErrClientIsOnline             ErrorCode = 523
+	ErrClientIsFlooding           ErrorCode = 524
+	ErrClientLoginNotPermitted    ErrorCode = 527
+	ErrClientNotSubscribed        ErrorCode = 528
+
+	ErrChannelInvalidID               ErrorCode = 768
+	ErrChannelProtocolLimitReached    ErrorCode = 769
+	ErrChannelAlreadyIn               ErrorCode = 770
+	ErrChannelNameInUse               ErrorCode = 771
+	ErrChannelNotEmpty                ErrorCode = 772
+	ErrChannelCannotDeleteDefault     ErrorCode = 773
+	ErrChannelDefaultRequirePermanent ErrorCode = 774
+	ErrChannelInvalidFlags            ErrorCode = 775
+	ErrChannelParentNotPermanent      ErrorCode = 776
+	ErrChannelMaxClientsReached       ErrorCode = 777
+	ErrChannelMaxFamilyReached        ErrorCode = 778
+	ErrChannelInvalidOrder            ErrorCode = 779
+	ErrChannelNoFileTransferSupport   ErrorCode = 780
+	ErrChannelInvalidPassword         ErrorCode = 781
+
+	ErrServerInvalidID         ErrorCode = 1024
+	ErrServerRunning           ErrorCode = 1025
+	ErrServerIsShuttingDown    ErrorCode = 1026
+	ErrServerMaxClientsReached ErrorCode = 1027
+	ErrServerInvalidPassword   ErrorCode = 1028
+	ErrServerDeploymentActive  ErrorCode = 1029
+	ErrServerUnableToStopOwn   ErrorCode = 1030
+	ErrServerIsVirtual         ErrorCode = 1031
+	ErrServerWrongMachineID    ErrorCode = 1032
+	ErrServerIsNotRunning      ErrorCode = 1033
+	ErrServerIsBooting         ErrorCode = 1034
+	ErrServerStatusInvalid     ErrorCode = 1035
+
+	ErrDatabase                ErrorCode = 1280
+	ErrDatabaseEmptyResult     ErrorCode = 1281
+	ErrDatabaseDuplicateEntry  ErrorCode = 1282
+	ErrDatabaseNoModifications ErrorCode = 1283
+	ErrDatabaseConstraint      ErrorCode = 1284
+	ErrDatabaseReinvoke        ErrorCode = 1285
+
+	ErrParameterQuote        ErrorCode = 1536
+	ErrParameterInvalidCount ErrorCode = 1537
+	ErrParameterInvalid      ErrorCode = 1538
+	ErrParameterNotFound     ErrorCode = 1539
+	ErrParameterConvert      ErrorCode = 1540
+	ErrParameterInvalidSize  ErrorCode = 1541
+	ErrParameterMissing      ErrorCode = 1542
+	ErrParameterChecksum     ErrorCode = 1543
+
+	ErrFileInvalidName        ErrorCode = 2048
+	ErrFileInvalidPermissions ErrorCode = 2049
+	ErrFileAlreadyExists      ErrorCode = 2050
+	ErrFileNotFound           ErrorCode = 2051
+	ErrFileIOError            ErrorCode = 2052
+	ErrFileInvalidTransferID  ErrorCode = 2053
+	ErrFileInvalidPath        ErrorCode = 2054
+	ErrFileNoFilesAvailable   ErrorCode = 2055
+	ErrFileInvalidSize        ErrorCode = 2057
+	ErrFileAlreadyInUse       ErrorCode = 2058
+	ErrFileNoSpaceLeft        ErrorCode = 2060
+
+	ErrPermissionInvalidGroupID         ErrorCode = 2560
+	ErrPermissionDuplicateEntry         ErrorCode = 2561
+	ErrPermissionInvalidPermID          ErrorCode = 2562
+	ErrPermissionEmptyResult            ErrorCode = 2563
+	ErrPermissionDefaultGroupForbidden  ErrorCode = 2564
+	ErrPermissionInvalidSize            ErrorCode = 2565
+	ErrPermissionInvalidValue           ErrorCode = 2566
+	ErrPermissionGroupNotEmpty          ErrorCode = 2567
+	ErrPermissionClientInsufficient     ErrorCode = 2568
+	ErrPermissionInsufficientGroupPower ErrorCode = 2569
+	ErrPermissionInsufficientPermPower  ErrorCode = 2570
+	ErrPermissionTemplateGroupIsUsed    ErrorCode = 2571
+	ErrPermission                       ErrorCode = 2572
+
+	ErrAccountingVirtualServerLimit ErrorCode = 2816
+	ErrAccountingSlotLimit          ErrorCode = 2817
+
+	ErrMessageInvalidID ErrorCode = 3072
+
+	ErrBanInvalidID        ErrorCode = 3328
+	ErrConnectFailedBanned ErrorCode = 3329
+	ErrRenameFailedBanned  ErrorCode = 3330
+	ErrBanFlooding         ErrorCode = 3331
+
+	ErrPrivilegeKeyInvalid ErrorCode = 3840
+)
+
+// ErrorCategory is a broad classification of an ErrorCode.
+type ErrorCategory int
+
+// Error categories.
+const (
+	// CategoryOther is any error not in a more specific category.
+	CategoryOther ErrorCategory = iota
+
+	// CategoryNotFound is an unknown ID, file or key, or an empty result.
+	CategoryNotFound
+
+	// CategoryPermission is insufficient permissions, a missing login or a ban.
+	CategoryPermission
+
+	// CategoryFlood is flood protection being triggered.
+	CategoryFlood
+
+	// CategoryParameter is a missing or invalid command parameter.
+	CategoryParameter
+)
+
+// String implements fmt.Stringer.
+func (c ErrorCategory) String() string {
+	switch c {
+	case CategoryNotFound:
+		return "not found"
+	case CategoryPermission:
+		return "permission"
+	case CategoryFlood:
+		return "flood"
+	case CategoryParameter:
+		return "parameter"
+	default:
+		return "other"
+	}
+}
+
+// errorCodes are the messages and categories of the known error codes.
+var errorCodes = map[ErrorCode]struct {
+	msg string
+	cat ErrorCategory
+}{
+	ErrOK:                     {"ok", CategoryOther},
+	ErrUndefined:              {"undefined error", CategoryOther},
+	ErrNotImplemented:         {"not implemented", CategoryOther},
+	ErrTimeLimitReached:       {"time limit reached", CategoryOther},
+	ErrCommandNotFound:        {"command not found", CategoryParameter},
+	ErrUnableToBindPort:       {"unable to bind network port", CategoryOther},
+	ErrNoNetworkPortAvailable: {"no network port available", CategoryOther},
+
+	ErrClientInvalidID:            {"invalid clientID", CategoryNotFound},
+	ErrClientNicknameInUse:        {"nickname is already in use", CategoryOther},
+	ErrClientProtocolLimitReached: {"max clients protocol limit reached", CategoryOther},
+	ErrClientInvalidType:          {"invalid client type", CategoryParameter},
+	ErrClientAlreadySubscribed:    {"already subscribed", CategoryOther},
+	ErrClientNotLoggedIn:          {"not logged in", CategoryPermission},
+	ErrClientInvalidPassword:      {"invalid loginname or password", CategoryPermission},
+	ErrClientTooManyClones:        {"too many clones already connected", CategoryOther},
+	ErrClientVersionOutdated:      {"client version outdated, please update", CategoryOther},
+	ErrClientIsOnline:             {"client is online", CategoryOther},
+	ErrClientIsFlooding:           {"client is flooding", CategoryFlood},
+	ErrClientLoginNotPermitted:    {"login not permitted", CategoryPermission},
+	ErrClientNotSubscribed:        {"not subscribed", CategoryOther},
+
+	ErrChannelInvalidID:               {"invalid channelID", CategoryNotFound},
+	ErrChannelProtocolLimitReached:    {"max channels protocol limit reached", CategoryOther},
+	ErrChannelAlreadyIn:               {"already member of channel", CategoryOther},
+	ErrChannelNameInUse:               {"channel name is already in use", CategoryOther},
+	ErrChannelNotEmpty:                {"channel not empty", CategoryOther},
+	ErrChannelCannotDeleteDefault:     {"can not delete default channel", CategoryOther},
+	ErrChannelDefaultRequirePermanent: {"default channel requires permanent", CategoryParameter},
+	ErrChannelInvalidFlags:            {"invalid channel flags", CategoryParameter},
+	ErrChannelParentNotPermanent:      {"permanent channel can not be child of non permanent channel", CategoryParameter},
+	ErrChannelMaxClientsReached:       {"channel maxclient reached", CategoryOther},
+	ErrChannelMaxFamilyReached:        {"channel maxfamily reached", CategoryOther},
+	ErrChannelInvalidOrder:            {"invalid channel order", CategoryParameter},
+	ErrChannelNoFileTransferSupport:   {"channel does not support filetransfers", CategoryOther},
+	ErrChannelInvalidPassword:         {"invalid channel password", CategoryPermission},
+
+	ErrServerInvalidID:         {"invalid serverID", CategoryNotFound},
+	ErrServerRunning:           {"server is running", CategoryOther},
+	ErrServerIsShuttingDown:    {"server is shutting down", CategoryOther},
+	ErrServerMaxClientsReached: {"server maxclient reached", CategoryOther},
+	ErrServerInvalidPassword:   {"invalid server password", CategoryPermission},
+	ErrServerDeploymentActive:  {"deployment active", CategoryOther},
+	ErrServerUnableToStopOwn:   {"unable to stop own server in your connection class", CategoryOther},
+	ErrServerIsVirtual:         {"server is virtual", CategoryOther},
+	ErrServerWrongMachineID:    {"server wrong machineID", CategoryOther},
+	ErrServerIsNotRunning:      {"server is not running", CategoryOther},
+	ErrServerIsBooting:         {"server is booting up", CategoryOther},
+	ErrServerStatusInvalid:     {"server got an invalid status for this operation", CategoryOther},
+
+	ErrDatabase:                {"database error", CategoryOther},
+	ErrDatabaseEmptyResult:     {"database empty result set", CategoryNotFound},
+	ErrDatabaseDuplicateEntry:  {"database duplicate entry", CategoryOther},
+	ErrDatabaseNoModifications: {"database no modifications", CategoryOther},
+	ErrDatabaseConstraint:      {"database invalid constraint", CategoryOther},
+	ErrDatabaseReinvoke:        {"database reinvoke command", CategoryOther},
+
+	ErrParameterQuote:        {"invalid quote", CategoryParameter},
+	ErrParameterInvalidCount: {"invalid parameter count", CategoryParameter},
+	ErrParameterInvalid:      {"invalid parameter", CategoryParameter},
+	ErrParameterNotFound:     {"parameter not found", CategoryParameter},
+	ErrParameterConvert:      {"convert error", CategoryParameter},
+	ErrParameterInvalidSize:  {"invalid parameter size", CategoryParameter},
+	ErrParameterMissing:      {"missing required parameter", CategoryParameter},
+	ErrParameterChecksum:     {"invalid checksum", CategoryParameter},
+
+	ErrFileInvalidName:        {"invalid file name", CategoryParameter},
+	ErrFileInvalidPermissions: {"invalid file permissions", CategoryPermission},
+	ErrFileAlreadyExists:      {"file already exists", CategoryOther},
+	ErrFileNotFound:           {"file not found", CategoryNotFound},
+	ErrFileIOError:            {"file input/output error", CategoryOther},
+	ErrFileInvalidTransferID:  {"invalid file transfer ID", CategoryNotFound},
+	ErrFileInvalidPath:        {"invalid file path", CategoryParameter},
+	ErrFileNoFilesAvailable:   {"no files available", CategoryNotFound},
+	ErrFileInvalidSize:        {"invalid file size", CategoryParameter},
+	ErrFileAlreadyInUse:       {"file already in use", CategoryOther},
+	ErrFileNoSpaceLeft:        {"no space left on device", CategoryOther},
+
+	ErrPermissionInvalidGroupID:         {"invalid group ID", CategoryNotFound},
+	ErrPermissionDuplicateEntry:         {"duplicate entry", CategoryOther},
+	ErrPermissionInvalidPermID:          {"invalid permission ID", CategoryNotFound},
+	ErrPermissionEmptyResult:            {"empty result set", CategoryNotFound},
+	ErrPermissionDefaultGroupForbidden:  {"access to default group is forbidden", CategoryPermission},
+	ErrPermissionInvalidSize:            {"invalid size", CategoryParameter},
+	ErrPermissionInvalidValue:           {"invalid value", CategoryParameter},
+	ErrPermissionGroupNotEmpty:          {"group is not empty", CategoryOther},
+	ErrPermissionClientInsufficient:     {"insufficient client permissions", CategoryPermission},
+	ErrPermissionInsufficientGroupPower: {"insufficient group modify power", CategoryPermission},
+	ErrPermissionInsufficientPermPower:  {"insufficient permission modify power", CategoryPermission},
+	ErrPermissionTemplateGroupIsUsed:    {"template group is currently used", CategoryOther},
+	ErrPermission:                       {"permission error", CategoryPermission},
+
+	ErrAccountingVirtualServerLimit: {"virtualserver limit reached", CategoryOther},
+	ErrAccountingSlotLimit:          {"max slot limit reached", CategoryOther},
+
+	ErrMessageInvalidID: {"invalid message id", CategoryNotFound},
+
+	ErrBanInvalidID:        {"invalid ban id", CategoryNotFound},
+	ErrConnectFailedBanned: {"connection failed, you are banned", CategoryPermission},
+	ErrRenameFailedBanned:  {"rename failed, new name is banned", CategoryPermission},
+	ErrBanFlooding:         {"flood ban", CategoryFlood},
+
+	ErrPrivilegeKeyInvalid: {"invalid privilege key", CategoryNotFound},
+}
+
+// Error implements error, returning the message the server sends for the code.
+func (c ErrorCode) Error() string {
+	if e, ok := errorCodes[c]; ok {
+		return e.msg
+	}
+	return "error " + strconv.Itoa(int(c))
+}
+
+// Category returns the category of the code.
+func (c ErrorCode) Category() ErrorCategory {
+	return errorCodes[c].cat
+}
+
+// Code returns the ErrorCode of e.
+func (e *Error) Code() ErrorCode {
+	return ErrorCode(e.ID)
+}
+
+// Category returns the category of e.
+func (e *Error) Category() ErrorCategory {
+	return e.Code().Category()
+}
+
+// Is returns true if target is the ErrorCode of e, or an *Error with the same ID.
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorCode:
+		return e.ID == int(t)
+	case *Error:
+		return e.ID == t.ID
+	}
+	return false
+}
+
+// As sets target to the code of e if it's an *ErrorCode.
+func (e *Error) As(target interface{}) bool {
+	if c, ok := target.(*ErrorCode); ok {
+		*c = e.Code()
+		return true
+	}
+	return false
+}
+
+// FailedPermID returns the ID of the permission which caused the error and
+// true if the server reported it.
+func (e *Error) FailedPermID() (int, bool) {
+	id, ok := e.Details["failed_permid"].(int)
+	return id, ok
+}
+
+// ExtraMsg returns the additional message sent by the server, if any.
+func (e *Error) ExtraMsg() string {
+	switch v := e.Details["extra_msg"].(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	}
+	return ""
+}
+
+// CategoryOf returns the category of the *Error in the chain of err,
+// CategoryOther if there is none.
+func CategoryOf(err error) ErrorCategory {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Category()
+	}
+	return CategoryOther
+}
@@ -12,7 +12,8 @@ var (
 	// doesn't respond with the required connection header.
 	ErrInvalidConnectHeader = errors.New("invalid connect header")
 
-	// ErrNilOption is returned by NewClient if an option is nil.
+	// ErrNilOption is returned by NewClient and the constructors of the
+	// subpackages if an option is nil.
 	ErrNilOption = errors.New("nil option")
 
 	// ErrNotConnected is returned by Exec and ExecCmd if the client is not connected.
@@ -21,6 +22,19 @@ var (
 	// ErrTimeout is returned by Exec and ExecCmd if no response is received
 	// within the specified timeout duration.
 	ErrTimeout = errors.New("timeout")
+
+	// ErrInvalidSnapshot is returned by SnapshotRead if the input isn't a valid snapshot file.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+	// ErrPoolClosed is returned by Pool.Get if the pool is closed.
+	ErrPoolClosed = errors.New("pool closed")
+
+	// ErrCloneNoSource is returned by Clone if no source server is specified.
+	ErrCloneNoSource = errors.New("clone: no source server")
+
+	// ErrQueryLoginDeleted is returned by QueryLoginRotate if the login was
+	// deleted but the new login couldn't be added.
+	ErrQueryLoginDeleted = errors.New("query login deleted")
 )
 
 // Error represents a error returned from the TeamSpeak 3 server.
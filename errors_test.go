@@ -1,9 +1,12 @@
 package ts3
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewError(t *testing.T) {
@@ -65,3 +68,193 @@ func TestNewInvalidResponseError(t *testing.T) {
 	assert.Equal(t, reason, err.Reason)
 	assert.Equal(t, lines, err.Data)
 }
+
+func TestErrorCode(t *testing.T) {
+	perm := &Error{
+		ID:      2568,
+		Msg:     "insufficient client permissions",
+		Details: map[string]interface{}{"failed_permid": 4, "extra_msg": "need more power"},
+	}
+	err := fmt.Errorf("wrapped: %w", perm)
+
+	assert.True(t, errors.Is(err, ErrPermissionClientInsufficient))
+	assert.False(t, errors.Is(err, ErrClientInvalidID))
+	assert.True(t, errors.Is(err, &Error{ID: 2568}))
+	assert.False(t, errors.Is(errors.New("other"), ErrPermissionClientInsufficient))
+
+	var code ErrorCode
+	require.True(t, errors.As(err, &code))
+	assert.Equal(t, ErrPermissionClientInsufficient, code)
+
+	assert.Equal(t, ErrPermissionClientInsufficient, perm.Code())
+	assert.Equal(t, CategoryPermission, perm.Category())
+	assert.Equal(t, CategoryPermission, CategoryOf(err))
+	assert.Equal(t, CategoryOther, CategoryOf(errors.New("other")))
+
+	id, ok := perm.FailedPermID()
+	assert.True(t, ok)
+	assert.Equal(t, 4, id)
+	assert.Equal(t, "need more power", perm.ExtraMsg())
+
+	empty := &Error{ID: 1281, Msg: "database empty result set"}
+	_, ok = empty.FailedPermID()
+	assert.False(t, ok)
+	assert.Equal(t, "", empty.ExtraMsg())
+	assert.Equal(t, CategoryNotFound, empty.Category())
+
+	assert.Equal(t, "123", (&Error{Details: map[string]interface{}{"extra_msg": 123}}).ExtraMsg())
+
+	tests := []struct {
+		code ErrorCode
+		msg  string
+		cat  ErrorCategory
+	}{
+		{ErrClientInvalidID, "invalid clientID", CategoryNotFound},
+		{ErrChannelInvalidID, "invalid channelID", CategoryNotFound},
+		{ErrDatabaseEmptyResult, "database empty result set", CategoryNotFound},
+		{ErrFileNotFound, "file not found", CategoryNotFound},
+		{ErrClientIsFlooding, "client is flooding", CategoryFlood},
+		{ErrParameterMissing, "missing required parameter", CategoryParameter},
+		{ErrPermissionClientInsufficient, "insufficient client permissions", CategoryPermission},
+		{ErrorCode(9999), "error 9999", CategoryOther},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.msg, tc.code.Error())
+		assert.Equal(t, tc.cat, tc.code.Category(), tc.code.Error())
+	}
+
+	assert.Equal(t, "not found", CategoryNotFound.String())
+	assert.Equal(t, "permission", CategoryPermission.String())
+	assert.Equal(t, "flood", CategoryFlood.String())
+	assert.Equal(t, "parameter", CategoryParameter.String())
+	assert.Equal(t, "other", CategoryOther.String())
+}
+
+// TestErrorCodeValues pins the error codes to the values of the
+// ERROR_* constants in the TeamSpeak SDK public_errors.h.
+func TestErrorCodeValues(t *testing.T) {
+	sdk := map[ErrorCode]int{
+		ErrOK:                     0x0000, // ERROR_ok
+		ErrUndefined:              0x0001, // ERROR_undefined
+		ErrNotImplemented:         0x0002, // ERROR_not_implemented
+		ErrTimeLimitReached:       0x0005, // ERROR_lib_time_limit_reached
+		ErrCommandNotFound:        0x0100, // ERROR_command_not_found
+		ErrUnableToBindPort:       0x0101, // ERROR_unable_to_bind_network_port
+		ErrNoNetworkPortAvailable: 0x0102, // ERROR_no_network_port_available
+
+		ErrClientInvalidID:            0x0200, // ERROR_client_invalid_id
+		ErrClientNicknameInUse:        0x0201, // ERROR_client_nickname_inuse
+		ErrClientProtocolLimitReached: 0x0203, // ERROR_client_protocol_limit_reached
+		ErrClientInvalidType:          0x0204, // ERROR_client_invalid_type
+		ErrClientAlreadySubscribed:    0x0205, // ERROR_client_already_subscribed
+		ErrClientNotLoggedIn:          0x0206, // ERROR_client_not_logged_in
+		ErrClientInvalidPassword:      0x0208, // ERROR_client_invalid_password
+		ErrClientTooManyClones:        0x0209, // ERROR_client_too_many_clones_connected
+		ErrClientVersionOutdated:      0x020a, // ERROR_client_version_outdated
+		ErrClientIsOnline:             0x020b, // ERROR_client_is_online
+		ErrClientIsFlooding:           0x020c, // ERROR_client_is_flooding
+		ErrClientLoginNotPermitted:    0x020f, // ERROR_client_login_not_permitted
+		ErrClientNotSubscribed:        0x0210, // ERROR_client_not_subscribed
+
+		ErrChannelInvalidID:               0x0300, // ERROR_channel_invalid_id
+		ErrChannelProtocolLimitReached:    0x0301, // ERROR_channel_protocol_limit_reached
+		ErrChannelAlreadyIn:               0x0302, // ERROR_channel_already_in
+		ErrChannelNameInUse:               0x0303, // ERROR_channel_name_inuse
+		ErrChannelNotEmpty:                0x0304, // ERROR_channel_not_empty
+		ErrChannelCannotDeleteDefault:     0x0305, // ERROR_channel_can_not_delete_default
+		ErrChannelDefaultRequirePermanent: 0x0306, // ERROR_channel_default_require_permanent
+		ErrChannelInvalidFlags:            0x0307, // ERROR_channel_invalid_flags
+		ErrChannelParentNotPermanent:      0x0308, // ERROR_channel_parent_not_permanent
+		ErrChannelMaxClientsReached:       0x0309, // ERROR_channel_maxclients_reached
+		ErrChannelMaxFamilyReached:        0x030a, // ERROR_channel_maxfamily_reached
+		ErrChannelInvalidOrder:            0x030b, // ERROR_channel_invalid_order
+		ErrChannelNoFileTransferSupport:   0x030c, // ERROR_channel_no_filetransfer_supported
+		ErrChannelInvalidPassword:         0x030d, // ERROR_channel_invalid_password
+
+		ErrServerInvalidID:         0x0400, // ERROR_server_invalid_id
+		ErrServerRunning:           0x0401, // ERROR_server_running
+		ErrServerIsShuttingDown:    0x0402, // ERROR_server_is_shutting_down
+		ErrServerMaxClientsReached: 0x0403, // ERROR_server_maxclients_reached
+		ErrServerInvalidPassword:   0x0404, // ERROR_server_invalid_password
+		ErrServerDeploymentActive:  0x0405, // ERROR_server_deployment_active
+		ErrServerUnableToStopOwn:   0x0406, // ERROR_server_unable_to_stop_own_server
+		ErrServerIsVirtual:         0x0407, // ERROR_server_is_virtual
+		ErrServerWrongMachineID:    0x0408, // ERROR_server_wrong_machineid
+		ErrServerIsNotRunning:      0x0409, // ERROR_server_is_not_running
+		ErrServerIsBooting:         0x040a, // ERROR_server_is_booting
+		ErrServerStatusInvalid:     0x040b, // ERROR_server_status_invalid
+
+		ErrDatabase:                0x0500, // ERROR_database
+		ErrDatabaseEmptyResult:     0x0501, // ERROR_database_empty_result
+		ErrDatabaseDuplicateEntry:  0x0502, // ERROR_database_duplicate_entry
+		ErrDatabaseNoModifications: 0x0503, // ERROR_database_no_modifications
+		ErrDatabaseConstraint:      0x0504, // ERROR_database_constraint
+		ErrDatabaseReinvoke:        0x0505, // ERROR_database_reinvoke
+
+		ErrParameterQuote:        0x0600, // ERROR_parameter_quote
+		ErrParameterInvalidCount: 0x0601, // ERROR_parameter_invalid_count
+		ErrParameterInvalid:      0x0602, // ERROR_parameter_invalid
+		ErrParameterNotFound:     0x0603, // ERROR_parameter_not_found
+		ErrParameterConvert:      0x0604, // ERROR_parameter_convert
+		ErrParameterInvalidSize:  0x0605, // ERROR_parameter_invalid_size
+		ErrParameterMissing:      0x0606, // ERROR_parameter_missing
+		ErrParameterChecksum:     0x0607, // ERROR_parameter_checksum
+
+		ErrFileInvalidName:        0x0800, // ERROR_file_invalid_name
+		ErrFileInvalidPermissions: 0x0801, // ERROR_file_invalid_permissions
+		ErrFileAlreadyExists:      0x0802, // ERROR_file_already_exists
+		ErrFileNotFound:           0x0803, // ERROR_file_not_found
+		ErrFileIOError:            0x0804, // ERROR_file_io_error
+		ErrFileInvalidTransferID:  0x0805, // ERROR_file_invalid_transfer_id
+		ErrFileInvalidPath:        0x0806, // ERROR_file_invalid_path
+		ErrFileNoFilesAvailable:   0x0807, // ERROR_file_no_files_available
+		ErrFileInvalidSize:        0x0809, // ERROR_file_invalid_size
+		ErrFileAlreadyInUse:       0x080a, // ERROR_file_already_in_use
+		ErrFileNoSpaceLeft:        0x080c, // ERROR_file_no_space_left_on_device
+
+		ErrPermissionInvalidGroupID:         0x0a00, // ERROR_permission_invalid_group_id
+		ErrPermissionDuplicateEntry:         0x0a01, // ERROR_permission_duplicate_entry
+		ErrPermissionInvalidPermID:          0x0a02, // ERROR_permission_invalid_perm_id
+		ErrPermissionEmptyResult:            0x0a03, // ERROR_permission_empty_result
+		ErrPermissionDefaultGroupForbidden:  0x0a04, // ERROR_permission_default_group_forbidden
+		ErrPermissionInvalidSize:            0x0a05, // ERROR_permission_invalid_size
+		ErrPermissionInvalidValue:           0x0a06, // ERROR_permission_invalid_value
+		ErrPermissionGroupNotEmpty:          0x0a07, // ERROR_permissions_group_not_empty
+		ErrPermissionClientInsufficient:     0x0a08, // ERROR_permissions_client_insufficient
+		ErrPermissionInsufficientGroupPower: 0x0a09, // ERROR_permissions_insufficient_group_power
+		ErrPermissionInsufficientPermPower:  0x0a0a, // ERROR_permissions_insufficient_permission_power
+		ErrPermissionTemplateGroupIsUsed:    0x0a0b, // ERROR_permission_template_group_is_used
+		ErrPermission:                       0x0a0c, // ERROR_permissions
+
+		ErrAccountingVirtualServerLimit: 0x0b00, // ERROR_accounting_virtualserver_limit_reached
+		ErrAccountingSlotLimit:          0x0b01, // ERROR_accounting_slot_limit_reached
+
+		ErrMessageInvalidID: 0x0c00, // ERROR_message_invalid_id
+
+		ErrBanInvalidID:        0x0d00, // ERROR_ban_invalid_id
+		ErrConnectFailedBanned: 0x0d01, // ERROR_connect_failed_banned
+		ErrRenameFailedBanned:  0x0d02, // ERROR_rename_failed_banned
+		ErrBanFlooding:         0x0d03, // ERROR_ban_flooding
+
+		ErrPrivilegeKeyInvalid: 0x0f00, // ERROR_privilege_key_invalid
+	}
+
+	require.Len(t, sdk, len(errorCodes), "codes missing from the catalog or test")
+	for code, value := range sdk {
+		assert.Equal(t, value, int(code), code.Error())
+		_, ok := errorCodes[code]
+		assert.True(t, ok, "%d not in catalog", value)
+	}
+}
+
+func TestErrorCodeFromServer(t *testing.T) {
+	matches := respTrailerRe.FindStringSubmatch(`error id=2568 msg=insufficient\sclient\spermissions failed_permid=4 extra_msg=try\sagain`)
+	require.Len(t, matches, 4)
+	err := NewError(matches)
+
+	assert.True(t, errors.Is(err, ErrPermissionClientInsufficient))
+	id, ok := err.FailedPermID()
+	assert.True(t, ok)
+	assert.Equal(t, 4, id)
+	assert.Equal(t, "try again", err.ExtraMsg())
+}
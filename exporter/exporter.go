@@ -0,0 +1,467 @@
+// Package exporter provides a Prometheus collector for TeamSpeak 3 instances.
+//
+// The Collector gathers serverinfo, serverrequestconnectioninfo, clientlist
+// and channellist for each online virtual server, plus the instance wide
+// hostinfo and instanceinfo, using clients from a ts3.Pool so virtual servers are scraped
+// concurrently:
+//
+//	pool, err := ts3.NewPool(4, func() (*ts3.Client, error) {
+//		c, err := ts3.NewClient(addr)
+//		if err != nil {
+//			return nil, err
+//		}
+//		if err := c.Login(user, pass); err != nil {
+//			c.Close()
+//			return nil, err
+//		}
+//		return c, nil
+//	})
+//	c, err := exporter.New(pool, exporter.Timeout(5*time.Second))
+//	prometheus.MustRegister(c)
+//
+// The exporter is a separate module so the Prometheus dependencies aren't
+// required by users of ts3 alone. It requires a released version of ts3, to
+// develop both together use a workspace from the repository root:
+//
+//	go work init . ./exporter
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultNamespace is the default namespace of the metrics.
+	DefaultNamespace = "ts3"
+
+	// DefaultTimeout is the default scrape timeout.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Server labels which can be passed to ServerLabels.
+const (
+	LabelServerID   = "server_id"
+	LabelServerPort = "server_port"
+	LabelServerName = "server_name"
+	LabelServerUID  = "server_uid"
+)
+
+// Server is the subset of ts3.ServerMethods used by a Collector.
+type Server interface {
+	Use(id int) error
+	List(options ...string) ([]*ts3.Server, error)
+	Info() (*ts3.Server, error)
+	ServerConnectionInfo() (*ts3.ServerConnectionInfo, error)
+	ClientList(options ...string) ([]*ts3.OnlineClient, error)
+	ChannelList(options ...string) ([]*ts3.Channel, error)
+	HostInfo() (*ts3.HostInfo, error)
+	InstanceInfo() (*ts3.Instance, error)
+}
+
+// pool provides the Servers used by a Collector.
+type pool interface {
+	Get(ctx context.Context) (Server, error)
+
+	// Put returns s to the pool, err is the error which occurred using it.
+	Put(s Server, err error)
+
+	// Discard releases s, which may still be in use, without reusing it.
+	Discard(s Server)
+}
+
+// clientPool is a pool backed by a ts3.Pool.
+type clientPool struct {
+	*ts3.Pool
+}
+
+// Get implements pool.
+func (p clientPool) Get(ctx context.Context) (Server, error) {
+	c, err := p.Pool.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Server, nil
+}
+
+// Put implements pool. Clients which returned an error which isn't a server
+// error are discarded as they may be in an unknown state.
+func (p clientPool) Put(s Server, err error) {
+	c := s.(*ts3.ServerMethods).Client
+
+	var serr *ts3.Error
+	if err != nil && !errors.As(err, &serr) {
+		p.Pool.Discard(c)
+		return
+	}
+
+	p.Pool.Put(c)
+}
+
+// Discard implements pool.
+func (p clientPool) Discard(s Server) {
+	p.Pool.Discard(s.(*ts3.ServerMethods).Client)
+}
+
+// Collector is a prometheus.Collector which collects metrics from all the
+// virtual servers of a TeamSpeak 3 instance.
+type Collector struct {
+	pool         pool
+	namespace    string
+	timeout      time.Duration
+	serverLabels []string
+	constLabels  prometheus.Labels
+	onError      func(error)
+
+	descs map[string]*prometheus.Desc
+}
+
+// Namespace sets the namespace of the metrics, default DefaultNamespace.
+func Namespace(namespace string) func(*Collector) error {
+	return func(c *Collector) error {
+		c.namespace = namespace
+		return nil
+	}
+}
+
+// Timeout sets the scrape timeout, default DefaultTimeout.
+func Timeout(timeout time.Duration) func(*Collector) error {
+	return func(c *Collector) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout %v: must be positive", timeout)
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// ServerLabels sets the labels which identify a virtual server, from
+// LabelServerID, LabelServerPort, LabelServerName and LabelServerUID.
+// The default is LabelServerID, LabelServerPort and LabelServerName.
+func ServerLabels(labels ...string) func(*Collector) error {
+	return func(c *Collector) error {
+		if len(labels) == 0 {
+			return errors.New("server labels: none given")
+		}
+		for _, l := range labels {
+			switch l {
+			case LabelServerID, LabelServerPort, LabelServerName, LabelServerUID:
+			default:
+				return fmt.Errorf("server labels: unknown label %q", l)
+			}
+		}
+		c.serverLabels = labels
+		return nil
+	}
+}
+
+// ConstLabels sets labels which are added to all metrics.
+func ConstLabels(labels prometheus.Labels) func(*Collector) error {
+	return func(c *Collector) error {
+		c.constLabels = labels
+		return nil
+	}
+}
+
+// Errors sets a function which is called with errors which occur during a scrape.
+func Errors(f func(error)) func(*Collector) error {
+	return func(c *Collector) error {
+		c.onError = f
+		return nil
+	}
+}
+
+// New returns a new Collector which uses clients from p.
+func New(p *ts3.Pool, options ...func(*Collector) error) (*Collector, error) {
+	return newCollector(clientPool{Pool: p}, options...)
+}
+
+// newCollector returns a new Collector which uses servers from p.
+func newCollector(p pool, options ...func(*Collector) error) (*Collector, error) {
+	c := &Collector{
+		pool:         p,
+		namespace:    DefaultNamespace,
+		timeout:      DefaultTimeout,
+		serverLabels: []string{LabelServerID, LabelServerPort, LabelServerName},
+		onError:      func(error) {},
+	}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(c); err != nil {
+			return nil, err
+		}
+	}
+
+	channelLabels := append(append([]string{}, c.serverLabels...), "channel_id", "channel_name")
+	c.descs = make(map[string]*prometheus.Desc)
+	for _, m := range []struct {
+		name, help string
+		labels     []string
+	}{
+		{"up", "Whether the last scrape of the instance was successful.", nil},
+		{"scrape_duration_seconds", "Duration of the last scrape of the instance.", nil},
+		{"instance_uptime_seconds", "Uptime of the instance.", nil},
+		{"instance_servers_running", "Number of virtual servers running.", nil},
+		{"instance_database_version", "Database version of the instance.", nil},
+		{"instance_permissions_version", "Permissions version of the instance.", nil},
+		{"instance_max_download_bandwidth_bytes_per_second", "Download bandwidth limit of the instance, not reported if unlimited.", nil},
+		{"instance_max_upload_bandwidth_bytes_per_second", "Upload bandwidth limit of the instance, not reported if unlimited.", nil},
+		{"server_up", "Whether the virtual server is online and was scraped successfully.", c.serverLabels},
+		{"server_clients_online", "Number of voice clients online, excluding query clients.", c.serverLabels},
+		{"server_query_clients_online", "Number of query clients online.", c.serverLabels},
+		{"server_max_clients", "Maximum number of clients.", c.serverLabels},
+		{"server_uptime_seconds", "Uptime of the virtual server.", c.serverLabels},
+		{"server_packet_loss_ratio", "Average packet loss of all clients.", c.serverLabels},
+		{"server_ping_seconds", "Average ping of all clients.", c.serverLabels},
+		{"server_bytes_sent_total", "Total bytes sent.", c.serverLabels},
+		{"server_bytes_received_total", "Total bytes received.", c.serverLabels},
+		{"server_bandwidth_sent_bytes_per_second", "Bandwidth sent in the last second.", c.serverLabels},
+		{"server_bandwidth_received_bytes_per_second", "Bandwidth received in the last second.", c.serverLabels},
+		{"channel_clients", "Number of voice clients in the channel.", channelLabels},
+	} {
+		c.descs[m.name] = prometheus.NewDesc(prometheus.BuildFQName(c.namespace, "", m.name), m.help, m.labels, c.constLabels)
+	}
+
+	return c, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range c.descs {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector. If the scrape doesn't complete
+// within the timeout only the up and scrape duration metrics are reported and
+// the clients it's still using are discarded.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	done := make(chan *scrape, 1)
+	go func() {
+		done <- c.scrape(ctx)
+	}()
+
+	var up float64
+	select {
+	case s := <-done:
+		if s.err != nil {
+			c.onError(s.err)
+		} else {
+			up = 1
+		}
+		for _, m := range s.metrics {
+			ch <- m
+		}
+	case <-ctx.Done():
+		c.onError(fmt.Errorf("exporter: scrape: %w", ctx.Err()))
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.descs["up"], prometheus.GaugeValue, up)
+	ch <- prometheus.MustNewConstMetric(c.descs["scrape_duration_seconds"], prometheus.GaugeValue, time.Since(start).Seconds())
+}
+
+// scrape is the result of a scrape.
+type scrape struct {
+	mtx     sync.Mutex
+	metrics []prometheus.Metric
+	err     error // err is the error which prevented the instance being scraped.
+}
+
+// add adds a metric to the scrape.
+func (s *scrape) add(desc *prometheus.Desc, t prometheus.ValueType, v float64, labels ...string) {
+	m := prometheus.MustNewConstMetric(desc, t, v, labels...)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.metrics = append(s.metrics, m)
+}
+
+// scrape collects the metrics of the instance and its virtual servers.
+func (c *Collector) scrape(ctx context.Context) *scrape {
+	s := &scrape{}
+
+	var servers []*ts3.Server
+	s.err = c.with(ctx, func(srv Server) error {
+		host, err := srv.HostInfo()
+		if err != nil {
+			return fmt.Errorf("exporter: host info: %w", err)
+		}
+		s.add(c.descs["instance_uptime_seconds"], prometheus.GaugeValue, host.Uptime.Seconds())
+		s.add(c.descs["instance_servers_running"], prometheus.GaugeValue, float64(host.ServersRunning))
+
+		inst, err := srv.InstanceInfo()
+		if err != nil {
+			return fmt.Errorf("exporter: instance info: %w", err)
+		}
+		s.add(c.descs["instance_database_version"], prometheus.GaugeValue, float64(inst.DatabaseVersion))
+		s.add(c.descs["instance_permissions_version"], prometheus.GaugeValue, float64(inst.PermissionsVersion))
+		if inst.MaxTotalDownloadBandwidth != math.MaxUint64 {
+			s.add(c.descs["instance_max_download_bandwidth_bytes_per_second"], prometheus.GaugeValue, float64(inst.MaxTotalDownloadBandwidth))
+		}
+		if inst.MaxTotalUploadBandwidth != math.MaxUint64 {
+			s.add(c.descs["instance_max_upload_bandwidth_bytes_per_second"], prometheus.GaugeValue, float64(inst.MaxTotalUploadBandwidth))
+		}
+
+		if servers, err = srv.List(ts3.ServerListUID); err != nil {
+			return fmt.Errorf("exporter: list servers: %w", err)
+		}
+		return nil
+	})
+	if s.err != nil {
+		return s
+	}
+
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		labels := c.labelValues(server)
+		if server.Status != "online" {
+			s.add(c.descs["server_up"], prometheus.GaugeValue, 0, labels...)
+			continue
+		}
+
+		wg.Add(1)
+		go func(server *ts3.Server) {
+			defer wg.Done()
+
+			var up float64
+			if err := c.scrapeServer(ctx, s, server.ID, labels); err != nil {
+				c.onError(err)
+			} else {
+				up = 1
+			}
+			s.add(c.descs["server_up"], prometheus.GaugeValue, up, labels...)
+		}(server)
+	}
+	wg.Wait()
+
+	return s
+}
+
+// scrapeServer collects the metrics of the virtual server id.
+func (c *Collector) scrapeServer(ctx context.Context, s *scrape, id int, labels []string) error {
+	return c.with(ctx, func(srv Server) error {
+		if err := srv.Use(id); err != nil {
+			return fmt.Errorf("exporter: server %d: use: %w", id, err)
+		}
+
+		info, err := srv.Info()
+		if err != nil {
+			return fmt.Errorf("exporter: server %d: info: %w", id, err)
+		}
+
+		conn, err := srv.ServerConnectionInfo()
+		if err != nil {
+			return fmt.Errorf("exporter: server %d: connection info: %w", id, err)
+		}
+
+		channels, err := srv.ChannelList()
+		if err != nil {
+			return fmt.Errorf("exporter: server %d: channel list: %w", id, err)
+		}
+
+		clients, err := srv.ClientList()
+		if err != nil {
+			return fmt.Errorf("exporter: server %d: client list: %w", id, err)
+		}
+
+		gauge := func(name string, v float64) {
+			s.add(c.descs[name], prometheus.GaugeValue, v, labels...)
+		}
+		gauge("server_clients_online", float64(info.ClientsOnline-info.QueryClientsOnline))
+		gauge("server_query_clients_online", float64(info.QueryClientsOnline))
+		gauge("server_max_clients", float64(info.MaxClients))
+		gauge("server_uptime_seconds", float64(info.Uptime))
+		gauge("server_packet_loss_ratio", info.TotalPacketLossTotal)
+		gauge("server_ping_seconds", float64(info.TotalPing)/1000)
+		gauge("server_bandwidth_sent_bytes_per_second", float64(conn.BandwidthSentLastSecond))
+		gauge("server_bandwidth_received_bytes_per_second", float64(conn.BandwidthReceivedLastSecond))
+		s.add(c.descs["server_bytes_sent_total"], prometheus.CounterValue, float64(conn.BytesSentTotal), labels...)
+		s.add(c.descs["server_bytes_received_total"], prometheus.CounterValue, float64(conn.BytesReceivedTotal), labels...)
+
+		population := make(map[int]int, len(channels))
+		for _, cl := range clients {
+			if cl.Type == 0 {
+				population[cl.ChannelID]++
+			}
+		}
+		for _, ch := range channels {
+			s.add(c.descs["channel_clients"], prometheus.GaugeValue, float64(population[ch.ID]),
+				append(append([]string{}, labels...), strconv.Itoa(ch.ID), ch.ChannelName)...)
+		}
+
+		return nil
+	})
+}
+
+// with calls f with a server from the pool, unless ctx is done. If ctx is
+// done before f returns the server is discarded, so a slow server doesn't
+// hold clients needed by the next scrape.
+func (c *Collector) with(ctx context.Context, f func(srv Server) error) error {
+	srv, err := c.pool.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("exporter: get client: %w", err)
+	}
+
+	// owned is taken by whichever of f returning or ctx being done
+	// releases srv back to the pool.
+	owned := make(chan struct{}, 1)
+	owned <- struct{}{}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			select {
+			case <-owned:
+				c.pool.Discard(srv)
+			default:
+			}
+		case <-stop:
+		}
+	}()
+
+	err = f(srv)
+	select {
+	case <-owned:
+		c.pool.Put(srv, err)
+	default:
+		// Discarded as ctx is done.
+	}
+
+	return err
+}
+
+// labelValues returns the values of the server labels for s.
+func (c *Collector) labelValues(s *ts3.Server) []string {
+	values := make([]string, len(c.serverLabels))
+	for i, l := range c.serverLabels {
+		switch l {
+		case LabelServerID:
+			values[i] = strconv.Itoa(s.ID)
+		case LabelServerPort:
+			values[i] = strconv.Itoa(s.Port)
+		case LabelServerName:
+			values[i] = s.Name
+		case LabelServerUID:
+			values[i] = s.UniqueIdentifier
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,347 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a Server which returns fixed data for the selected server.
+type fakeServer struct {
+	selected int
+	block    chan struct{}
+	fail     map[int]bool
+	pool     *fakePool
+}
+
+func (f *fakeServer) Use(id int) error {
+	if f.fail[id] {
+		return &ts3.Error{ID: int(ts3.ErrPermissionClientInsufficient), Msg: "insufficient client permissions"}
+	}
+	f.selected = id
+	return nil
+}
+
+func (f *fakeServer) List(options ...string) ([]*ts3.Server, error) {
+	return []*ts3.Server{
+		{ID: 1, Port: 9987, Name: "One", UniqueIdentifier: "uid1", Status: "online"},
+		{ID: 2, Port: 9988, Name: "Two", UniqueIdentifier: "uid2", Status: "online"},
+		{ID: 3, Port: 9989, Name: "Three", UniqueIdentifier: "uid3", Status: "offline"},
+	}, nil
+}
+
+func (f *fakeServer) Info() (*ts3.Server, error) {
+	if f.block != nil {
+		<-f.block
+	}
+	return &ts3.Server{
+		ClientsOnline:        3 * f.selected,
+		QueryClientsOnline:   1,
+		MaxClients:           32,
+		Uptime:               3600,
+		TotalPacketLossTotal: 0.05,
+		TotalPing:            25,
+	}, nil
+}
+
+func (f *fakeServer) ServerConnectionInfo() (*ts3.ServerConnectionInfo, error) {
+	return &ts3.ServerConnectionInfo{
+		BytesSentTotal:              1000,
+		BytesReceivedTotal:          2000,
+		BandwidthSentLastSecond:     10,
+		BandwidthReceivedLastSecond: 20,
+	}, nil
+}
+
+func (f *fakeServer) ClientList(options ...string) ([]*ts3.OnlineClient, error) {
+	clients := []*ts3.OnlineClient{{ID: 1, ChannelID: 1, Type: 1}}
+	for i := 0; i < 3*f.selected-1; i++ {
+		clients = append(clients, &ts3.OnlineClient{ID: i + 2, ChannelID: 1 + i%2})
+	}
+	return clients, nil
+}
+
+func (f *fakeServer) ChannelList(options ...string) ([]*ts3.Channel, error) {
+	return []*ts3.Channel{{ID: 1, ChannelName: "Lobby"}, {ID: 2, ChannelName: "Games"}, {ID: 3, ChannelName: "AFK"}}, nil
+}
+
+func (f *fakeServer) HostInfo() (*ts3.HostInfo, error) {
+	if f.pool != nil {
+		f.pool.mtx.Lock()
+		f.pool.hosts++
+		f.pool.mtx.Unlock()
+	}
+	return &ts3.HostInfo{Uptime: 2 * time.Hour, ServersRunning: 2}, nil
+}
+
+func (f *fakeServer) InstanceInfo() (*ts3.Instance, error) {
+	return &ts3.Instance{
+		DatabaseVersion:           26,
+		PermissionsVersion:        19,
+		MaxTotalDownloadBandwidth: math.MaxUint64,
+		MaxTotalUploadBandwidth:   1 << 20,
+	}, nil
+}
+
+// fakePool is a pool of fakeServers.
+type fakePool struct {
+	mtx       sync.Mutex
+	slots     chan struct{} // slots limits the servers in use if not nil.
+	block     chan struct{}
+	fail      map[int]bool
+	listErr   error
+	inUse     int
+	discarded int
+	hosts     int
+	errs      []error
+}
+
+func (p *fakePool) Get(ctx context.Context) (Server, error) {
+	if p.slots != nil {
+		select {
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		p.release()
+		return nil, err
+	}
+	p.inUse++
+	if p.listErr != nil {
+		return &failServer{fakeServer{}, p.listErr}, nil
+	}
+	return &fakeServer{block: p.block, fail: p.fail, pool: p}, nil
+}
+
+func (p *fakePool) Put(s Server, err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.inUse--
+	p.release()
+	if err != nil {
+		p.errs = append(p.errs, err)
+	}
+}
+
+func (p *fakePool) Discard(s Server) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.inUse--
+	p.discarded++
+	p.release()
+}
+
+// release frees a slot, p.mtx must be held.
+func (p *fakePool) release() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// failServer is a Server whose HostInfo fails.
+type failServer struct {
+	fakeServer
+	err error
+}
+
+func (f *failServer) HostInfo() (*ts3.HostInfo, error) {
+	return nil, f.err
+}
+
+// metrics are the metrics compared, excluding the scrape duration.
+var metrics = []string{
+	"ts3_up", "ts3_instance_uptime_seconds", "ts3_instance_servers_running",
+	"ts3_instance_database_version", "ts3_instance_permissions_version",
+	"ts3_instance_max_download_bandwidth_bytes_per_second", "ts3_instance_max_upload_bandwidth_bytes_per_second",
+	"ts3_server_up", "ts3_server_clients_online", "ts3_server_query_clients_online",
+	"ts3_server_max_clients", "ts3_server_uptime_seconds", "ts3_server_packet_loss_ratio",
+	"ts3_server_ping_seconds", "ts3_server_bytes_sent_total", "ts3_server_bytes_received_total",
+	"ts3_server_bandwidth_sent_bytes_per_second", "ts3_server_bandwidth_received_bytes_per_second",
+	"ts3_channel_clients",
+}
+
+func TestNew(t *testing.T) {
+	p := &fakePool{}
+	_, err := newCollector(p, nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+
+	_, err = newCollector(p, Timeout(0))
+	assert.Error(t, err)
+
+	_, err = newCollector(p, ServerLabels())
+	assert.Error(t, err)
+
+	_, err = newCollector(p, ServerLabels("bad"))
+	assert.Error(t, err)
+}
+
+func TestCollect(t *testing.T) {
+	p := &fakePool{fail: map[int]bool{2: true}}
+	var errs []error
+	var mtx sync.Mutex
+	c, err := newCollector(p,
+		ServerLabels(LabelServerID, LabelServerUID),
+		ConstLabels(prometheus.Labels{"instance": "ts1"}),
+		Errors(func(err error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	expected := `
+# HELP ts3_channel_clients Number of voice clients in the channel.
+# TYPE ts3_channel_clients gauge
+ts3_channel_clients{channel_id="1",channel_name="Lobby",instance="ts1",server_id="1",server_uid="uid1"} 1
+ts3_channel_clients{channel_id="2",channel_name="Games",instance="ts1",server_id="1",server_uid="uid1"} 1
+ts3_channel_clients{channel_id="3",channel_name="AFK",instance="ts1",server_id="1",server_uid="uid1"} 0
+# HELP ts3_instance_database_version Database version of the instance.
+# TYPE ts3_instance_database_version gauge
+ts3_instance_database_version{instance="ts1"} 26
+# HELP ts3_instance_max_upload_bandwidth_bytes_per_second Upload bandwidth limit of the instance, not reported if unlimited.
+# TYPE ts3_instance_max_upload_bandwidth_bytes_per_second gauge
+ts3_instance_max_upload_bandwidth_bytes_per_second{instance="ts1"} 1.048576e+06
+# HELP ts3_instance_permissions_version Permissions version of the instance.
+# TYPE ts3_instance_permissions_version gauge
+ts3_instance_permissions_version{instance="ts1"} 19
+# HELP ts3_instance_servers_running Number of virtual servers running.
+# TYPE ts3_instance_servers_running gauge
+ts3_instance_servers_running{instance="ts1"} 2
+# HELP ts3_instance_uptime_seconds Uptime of the instance.
+# TYPE ts3_instance_uptime_seconds gauge
+ts3_instance_uptime_seconds{instance="ts1"} 7200
+# HELP ts3_server_bandwidth_received_bytes_per_second Bandwidth received in the last second.
+# TYPE ts3_server_bandwidth_received_bytes_per_second gauge
+ts3_server_bandwidth_received_bytes_per_second{instance="ts1",server_id="1",server_uid="uid1"} 20
+# HELP ts3_server_bandwidth_sent_bytes_per_second Bandwidth sent in the last second.
+# TYPE ts3_server_bandwidth_sent_bytes_per_second gauge
+ts3_server_bandwidth_sent_bytes_per_second{instance="ts1",server_id="1",server_uid="uid1"} 10
+# HELP ts3_server_bytes_received_total Total bytes received.
+# TYPE ts3_server_bytes_received_total counter
+ts3_server_bytes_received_total{instance="ts1",server_id="1",server_uid="uid1"} 2000
+# HELP ts3_server_bytes_sent_total Total bytes sent.
+# TYPE ts3_server_bytes_sent_total counter
+ts3_server_bytes_sent_total{instance="ts1",server_id="1",server_uid="uid1"} 1000
+# HELP ts3_server_clients_online Number of voice clients online, excluding query clients.
+# TYPE ts3_server_clients_online gauge
+ts3_server_clients_online{instance="ts1",server_id="1",server_uid="uid1"} 2
+# HELP ts3_server_max_clients Maximum number of clients.
+# TYPE ts3_server_max_clients gauge
+ts3_server_max_clients{instance="ts1",server_id="1",server_uid="uid1"} 32
+# HELP ts3_server_packet_loss_ratio Average packet loss of all clients.
+# TYPE ts3_server_packet_loss_ratio gauge
+ts3_server_packet_loss_ratio{instance="ts1",server_id="1",server_uid="uid1"} 0.05
+# HELP ts3_server_ping_seconds Average ping of all clients.
+# TYPE ts3_server_ping_seconds gauge
+ts3_server_ping_seconds{instance="ts1",server_id="1",server_uid="uid1"} 0.025
+# HELP ts3_server_query_clients_online Number of query clients online.
+# TYPE ts3_server_query_clients_online gauge
+ts3_server_query_clients_online{instance="ts1",server_id="1",server_uid="uid1"} 1
+# HELP ts3_server_up Whether the virtual server is online and was scraped successfully.
+# TYPE ts3_server_up gauge
+ts3_server_up{instance="ts1",server_id="1",server_uid="uid1"} 1
+ts3_server_up{instance="ts1",server_id="2",server_uid="uid2"} 0
+ts3_server_up{instance="ts1",server_id="3",server_uid="uid3"} 0
+# HELP ts3_server_uptime_seconds Uptime of the virtual server.
+# TYPE ts3_server_uptime_seconds gauge
+ts3_server_uptime_seconds{instance="ts1",server_id="1",server_uid="uid1"} 3600
+# HELP ts3_up Whether the last scrape of the instance was successful.
+# TYPE ts3_up gauge
+ts3_up{instance="ts1"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), metrics...))
+	assert.Equal(t, 0, p.inUse)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "server 2")
+
+	// Registration checks the descriptions are consistent.
+	require.NoError(t, prometheus.NewPedanticRegistry().Register(c))
+}
+
+func TestCollectFailure(t *testing.T) {
+	p := &fakePool{listErr: errors.New("boom")}
+	c, err := newCollector(p)
+	require.NoError(t, err)
+
+	expected := `
+# HELP ts3_up Whether the last scrape of the instance was successful.
+# TYPE ts3_up gauge
+ts3_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), metrics...))
+}
+
+func TestCollectTimeout(t *testing.T) {
+	p := &fakePool{block: make(chan struct{})}
+	defer close(p.block)
+
+	var errs []error
+	var mtx sync.Mutex
+	c, err := newCollector(p, Timeout(20*time.Millisecond), Errors(func(err error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	expected := `
+# HELP ts3_up Whether the last scrape of the instance was successful.
+# TYPE ts3_up gauge
+ts3_up 0
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), metrics...))
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.NotEmpty(t, errs)
+	assert.True(t, errors.Is(errs[0], context.DeadlineExceeded))
+}
+
+func TestCollectSlowServer(t *testing.T) {
+	p := &fakePool{slots: make(chan struct{}, 1), block: make(chan struct{})}
+	defer close(p.block)
+
+	c, err := newCollector(p, Timeout(20*time.Millisecond))
+	require.NoError(t, err)
+
+	expected := `
+# HELP ts3_up Whether the last scrape of the instance was successful.
+# TYPE ts3_up gauge
+ts3_up 0
+`
+	// The client blocked on the slow server is discarded by the first
+	// scrape, so the second isn't starved of clients.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "ts3_up"))
+	}
+
+	require.Eventually(t, func() bool {
+		p.mtx.Lock()
+		defer p.mtx.Unlock()
+		return p.inUse == 0
+	}, time.Second, time.Millisecond)
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	assert.Equal(t, 2, p.hosts)
+	assert.GreaterOrEqual(t, p.discarded, 2)
+}
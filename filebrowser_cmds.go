@@ -0,0 +1,177 @@
+package ts3
+
+import (
+	"errors"
+	"path"
+	"time"
+)
+
+// isEmptyResult returns true if err indicates the server had no entries to return.
+func isEmptyResult(err error) bool {
+	return errors.Is(err, ErrDatabaseEmptyResult)
+}
+
+// FileEntry represents a file or directory in a channel's file repository.
+type FileEntry struct {
+	ChannelID int
+	Path      string // Path is the full path of the entry, e.g. /dir/file.txt.
+	Name      string // Name is the base name of the entry.
+	Size      int64
+	ModTime   time.Time
+	IsDir     bool
+}
+
+// fileEntry is a file entry as returned by the server.
+type fileEntry struct {
+	ChannelID int       `ms:"cid"`
+	Path      string    `ms:"path"`
+	Name      string    `ms:"name"`
+	Size      int64     `ms:"size"`
+	ModTime   time.Time `ms:"datetime"`
+	Type      int       `ms:"type"`
+}
+
+// FTGetFileList returns the files and directories in the directory dir of the channel cid.
+// The channel password cpw is only needed if the channel has a password.
+func (s *ServerMethods) FTGetFileList(cid int, cpw, dir string) ([]*FileEntry, error) {
+	var r []*fileEntry
+	if _, err := s.ExecCmd(NewCmd("ftgetfilelist").WithArgs(
+		NewArg("cid", cid),
+		NewArg("cpw", cpw),
+		NewArg("path", dir),
+	).WithResponse(&r)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]*FileEntry, len(r))
+	for i, e := range r {
+		entries[i] = &FileEntry{
+			ChannelID: cid,
+			Path:      path.Join(dir, e.Name),
+			Name:      e.Name,
+			Size:      e.Size,
+			ModTime:   e.ModTime,
+			IsDir:     e.Type == 0,
+		}
+	}
+
+	return entries, nil
+}
+
+// FTGetFileInfo returns information about the files names, specified as full paths,
+// in the channel cid. IsDir isn't set as the server doesn't return it.
+func (s *ServerMethods) FTGetFileInfo(cid int, cpw string, names ...string) ([]*FileEntry, error) {
+	args := make([]CmdArg, len(names))
+	for i, name := range names {
+		args[i] = NewArgSet(NewArg("cid", cid), NewArg("cpw", cpw), NewArg("name", name))
+	}
+
+	var r []*fileEntry
+	if _, err := s.ExecCmd(NewCmd("ftgetfileinfo").WithArgs(NewArgGroup(args...)).WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*FileEntry, len(r))
+	for i, e := range r {
+		entries[i] = &FileEntry{
+			ChannelID: e.ChannelID,
+			Path:      e.Name,
+			Name:      path.Base(e.Name),
+			Size:      e.Size,
+			ModTime:   e.ModTime,
+		}
+	}
+
+	return entries, nil
+}
+
+// FTCreateDir creates the directory dir in the channel cid.
+func (s *ServerMethods) FTCreateDir(cid int, cpw, dir string) error {
+	_, err := s.ExecCmd(NewCmd("ftcreatedir").WithArgs(
+		NewArg("cid", cid),
+		NewArg("cpw", cpw),
+		NewArg("dirname", dir),
+	))
+	return err
+}
+
+// FTDeleteFile deletes the files or directories names, specified as full paths, from the channel cid.
+func (s *ServerMethods) FTDeleteFile(cid int, cpw string, names ...string) error {
+	args := make([]CmdArg, len(names))
+	for i, name := range names {
+		args[i] = NewArg("name", name)
+	}
+
+	_, err := s.ExecCmd(NewCmd("ftdeletefile").WithArgs(
+		NewArg("cid", cid),
+		NewArg("cpw", cpw),
+		NewArgGroup(args...),
+	))
+	return err
+}
+
+// FTRenameFile renames the file oldName to newName in the channel cid.
+func (s *ServerMethods) FTRenameFile(cid int, cpw, oldName, newName string) error {
+	_, err := s.ExecCmd(NewCmd("ftrenamefile").WithArgs(
+		NewArg("cid", cid),
+		NewArg("cpw", cpw),
+		NewArg("oldname", oldName),
+		NewArg("newname", newName),
+	))
+	return err
+}
+
+// FTMoveFile moves the file oldName in the channel cid to newName in the channel tcid.
+// The channel password tcpw is only needed if the target channel has a password.
+func (s *ServerMethods) FTMoveFile(cid int, cpw, oldName string, tcid int, tcpw, newName string) error {
+	_, err := s.ExecCmd(NewCmd("ftrenamefile").WithArgs(
+		NewArg("cid", cid),
+		NewArg("cpw", cpw),
+		NewArg("tcid", tcid),
+		NewArg("tcpw", tcpw),
+		NewArg("oldname", oldName),
+		NewArg("newname", newName),
+	))
+	return err
+}
+
+// FileTransferStatus represents a running file transfer as returned by FTList.
+type FileTransferStatus struct {
+	ClientID     int     `ms:"clid"`
+	Path         string  `ms:"path"`
+	Name         string  `ms:"name"`
+	Size         int64   `ms:"size"`
+	SizeDone     int64   `ms:"sizedone"`
+	ClientFTID   int     `ms:"clientftfid"`
+	ServerFTID   int     `ms:"serverftfid"`
+	Sender       int     `ms:"sender"`
+	Status       int     `ms:"status"`
+	CurrentSpeed float64 `ms:"current_speed"` // CurrentSpeed is in bytes per second.
+	AverageSpeed float64 `ms:"average_speed"` // AverageSpeed is in bytes per second.
+	Runtime      int     `ms:"runtime"`       // Runtime is the duration of the transfer in milliseconds.
+}
+
+// FTList returns the running file transfers on the selected virtual server.
+func (s *ServerMethods) FTList() ([]*FileTransferStatus, error) {
+	var transfers []*FileTransferStatus
+	if _, err := s.ExecCmd(NewCmd("ftlist").WithResponse(&transfers)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+// FTStop stops the running file transfer serverftfid, if del is true the partial file is deleted.
+func (s *ServerMethods) FTStop(serverftfid int, del bool) error {
+	_, err := s.ExecCmd(NewCmd("ftstop").WithArgs(
+		NewArg("serverftfid", serverftfid),
+		NewArg("delete", del),
+	))
+	return err
+}
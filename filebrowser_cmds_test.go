@@ -0,0 +1,110 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsFileBrowser(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	ftgetfilelist := func(t *testing.T) {
+		t.Helper()
+		entries, err := c.Server.FTGetFileList(5, "", "/")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*FileEntry{
+			{ChannelID: 5, Path: "/docs", Name: "docs", ModTime: time.Unix(1691527133, 0), IsDir: true},
+			{ChannelID: 5, Path: "/readme.txt", Name: "readme.txt", Size: 11, ModTime: time.Unix(1691527200, 0)},
+		}
+		assert.Equal(t, expected, entries)
+	}
+
+	ftgetfileinfo := func(t *testing.T) {
+		t.Helper()
+		entries, err := c.Server.FTGetFileInfo(5, "", "/readme.txt", "/docs/a.txt")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*FileEntry{
+			{ChannelID: 5, Path: "/readme.txt", Name: "readme.txt", Size: 11, ModTime: time.Unix(1691527200, 0)},
+			{ChannelID: 5, Path: "/docs/a.txt", Name: "a.txt", Size: 5, ModTime: time.Unix(1691527300, 0)},
+		}
+		assert.Equal(t, expected, entries)
+	}
+
+	ftcreatedir := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.FTCreateDir(5, "", "/new dir"))
+	}
+
+	ftdeletefile := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.FTDeleteFile(5, "", "/readme.txt", "/docs"))
+	}
+
+	ftrenamefile := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.FTRenameFile(5, "", "/readme.txt", "/README.txt"))
+		assert.NoError(t, c.Server.FTMoveFile(5, "", "/readme.txt", 6, "secret", "/readme.txt"))
+	}
+
+	ftlist := func(t *testing.T) {
+		t.Helper()
+		transfers, err := c.Server.FTList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*FileTransferStatus{{
+			ClientID:     42087,
+			Path:         "files/virtualserver_1/channel_5",
+			Name:         "big.iso",
+			Size:         1048576,
+			SizeDone:     524288,
+			ClientFTID:   1,
+			ServerFTID:   6,
+			Status:       1,
+			CurrentSpeed: 1024.5,
+			AverageSpeed: 512.25,
+			Runtime:      2500,
+		}}
+		assert.Equal(t, expected, transfers)
+	}
+
+	ftstop := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.FTStop(6, true))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"ftgetfilelist", ftgetfilelist},
+		{"ftgetfileinfo", ftgetfileinfo},
+		{"ftcreatedir", ftcreatedir},
+		{"ftdeletefile", ftdeletefile},
+		{"ftrenamefile", ftrenamefile},
+		{"ftlist", ftlist},
+		{"ftstop", ftstop},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
@@ -0,0 +1,284 @@
+package ts3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// FileTransfer represents an initiated file transfer as returned by FTInitUpload and FTInitDownload.
+type FileTransfer struct {
+	ClientFTID int    `ms:"clientftfid"`
+	ServerFTID int    `ms:"serverftfid"`
+	Key        string `ms:"ftkey"`
+	Port       int    `ms:"port"`
+	Size       int64  `ms:"size"`    // Size is the size of the file, only set for downloads.
+	SeekPos    int64  `ms:"seekpos"` // SeekPos is the offset the transfer starts at.
+
+	// Status and Msg are set if the server rejected the transfer.
+	Status int    `ms:"status"`
+	Msg    string `ms:"msg"`
+}
+
+// err returns an Error if the transfer was rejected, nil otherwise.
+func (ft *FileTransfer) err() error {
+	if ft.Status == 0 {
+		return nil
+	}
+	return &Error{ID: ft.Status, Msg: ft.Msg}
+}
+
+// TransferOptions represents the options of a file transfer.
+type TransferOptions struct {
+	// ChannelPassword is the password of the channel, if it has one.
+	ChannelPassword string
+
+	// Overwrite replaces an existing file when uploading.
+	Overwrite bool
+
+	// Resume continues a previous partial upload, the server determines the offset
+	// and the data already uploaded is skipped from the reader.
+	Resume bool
+
+	// Offset is the offset to start downloading from.
+	Offset int64
+
+	// Progress, if not nil, is called after each chunk of data is transferred with the
+	// number of bytes of the file transferred so far, including any offset, and its size.
+	Progress func(transferred, size int64)
+}
+
+// FTInitUpload initiates the upload of a file of size bytes to path in the channel cid.
+// Use Upload to perform the transfer.
+func (s *ServerMethods) FTInitUpload(cid int, path string, size int64, opts *TransferOptions) (*FileTransfer, error) {
+	if opts == nil {
+		opts = &TransferOptions{}
+	}
+
+	ft := &FileTransfer{}
+	if _, err := s.ExecCmd(NewCmd("ftinitupload").WithArgs(
+		NewArg("clientftfid", s.nextFTID()),
+		NewArg("name", path),
+		NewArg("cid", cid),
+		NewArg("cpw", opts.ChannelPassword),
+		NewArg("size", size),
+		NewArg("overwrite", opts.Overwrite),
+		NewArg("resume", opts.Resume),
+	).WithResponse(ft)); err != nil {
+		return nil, err
+	}
+
+	return ft, ft.err()
+}
+
+// FTInitDownload initiates the download of the file path in the channel cid.
+// Use Download to perform the transfer.
+func (s *ServerMethods) FTInitDownload(cid int, path string, opts *TransferOptions) (*FileTransfer, error) {
+	if opts == nil {
+		opts = &TransferOptions{}
+	}
+
+	ft := &FileTransfer{}
+	if _, err := s.ExecCmd(NewCmd("ftinitdownload").WithArgs(
+		NewArg("clientftfid", s.nextFTID()),
+		NewArg("name", path),
+		NewArg("cid", cid),
+		NewArg("cpw", opts.ChannelPassword),
+		NewArg("seekpos", opts.Offset),
+	).WithResponse(ft)); err != nil {
+		return nil, err
+	}
+
+	if ft.SeekPos == 0 {
+		// Not all servers include the offset in the response.
+		ft.SeekPos = opts.Offset
+	}
+
+	return ft, ft.err()
+}
+
+// Upload uploads size bytes read from r to path in the channel cid.
+// opts may be nil to use the defaults.
+func (s *ServerMethods) Upload(ctx context.Context, cid int, path string, r io.Reader, size int64, opts *TransferOptions) error {
+	if opts == nil {
+		opts = &TransferOptions{}
+	}
+
+	ft, err := s.FTInitUpload(cid, path, size, opts)
+	if err != nil {
+		return err
+	}
+
+	if ft.SeekPos > 0 {
+		// Resuming, skip the data the server already has.
+		if rs, ok := r.(io.Seeker); ok {
+			_, err = rs.Seek(ft.SeekPos, io.SeekCurrent)
+		} else {
+			_, err = io.CopyN(io.Discard, r, ft.SeekPos)
+		}
+		if err != nil {
+			return fmt.Errorf("upload: skip: %w", err)
+		}
+	}
+
+	conn, err := s.dialFileTransfer(ctx, ft)
+	if err != nil {
+		return err
+	}
+
+	stop := closeOnDone(ctx, conn)
+	defer stop()
+
+	w := &progressWriter{Writer: conn, n: ft.SeekPos, size: size, progress: opts.Progress}
+	if _, err = io.CopyN(w, r, size-ft.SeekPos); err != nil {
+		conn.Close() //nolint: errcheck
+		if ctx.Err() != nil {
+			return fmt.Errorf("upload: %w", ctx.Err())
+		}
+		return fmt.Errorf("upload: copy: %w", err)
+	}
+
+	if err = conn.Close(); err != nil {
+		return fmt.Errorf("upload: close: %w", err)
+	}
+
+	return nil
+}
+
+// Download returns a reader for the file path in the channel cid.
+// opts may be nil to use the defaults.
+//
+// The caller must close the returned reader. The transfer is aborted if
+// ctx is done before the reader is closed.
+func (s *ServerMethods) Download(ctx context.Context, cid int, path string, opts *TransferOptions) (io.ReadCloser, error) {
+	if opts == nil {
+		opts = &TransferOptions{}
+	}
+
+	ft, err := s.FTInitDownload(cid, path, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.dialFileTransfer(ctx, ft)
+	if err != nil {
+		return nil, err
+	}
+
+	return &downloadReader{
+		conn:     conn,
+		r:        io.LimitReader(conn, ft.Size-ft.SeekPos),
+		n:        ft.SeekPos,
+		size:     ft.Size,
+		progress: opts.Progress,
+		stop:     closeOnDone(ctx, conn),
+	}, nil
+}
+
+// FileTransferHost sets the host used to connect to the file transfer port.
+// By default the host of the query connection is used.
+func FileTransferHost(host string) func(*Client) error {
+	return func(c *Client) error {
+		c.ftHost = host
+		return nil
+	}
+}
+
+// nextFTID returns a new client file transfer ID.
+func (s *ServerMethods) nextFTID() int {
+	return int(atomic.AddUint32(&s.ftID, 1) & 0xffff)
+}
+
+// dialFileTransfer connects to the file transfer port for ft and sends its key.
+func (s *ServerMethods) dialFileTransfer(ctx context.Context, ft *FileTransfer) (net.Conn, error) {
+	host := s.ftHost
+	if host == "" {
+		var err error
+		if host, _, err = net.SplitHostPort(s.conn.RemoteAddr().String()); err != nil {
+			return nil, fmt.Errorf("file transfer: host: %w", err)
+		}
+	}
+
+	d := &net.Dialer{Timeout: s.timeout}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(ft.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("file transfer: dial: %w", err)
+	}
+
+	if _, err := io.WriteString(conn, ft.Key); err != nil {
+		conn.Close() //nolint: errcheck
+		return nil, fmt.Errorf("file transfer: key: %w", err)
+	}
+
+	return conn, nil
+}
+
+// closeOnDone closes conn if ctx is done before the returned stop func is called.
+func closeOnDone(ctx context.Context, conn net.Conn) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close() //nolint: errcheck
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// progressWriter is an io.Writer which reports progress.
+type progressWriter struct {
+	io.Writer
+	n        int64
+	size     int64
+	progress func(transferred, size int64)
+}
+
+// Write implements io.Writer.
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	if w.progress != nil && n > 0 {
+		w.progress(w.n, w.size)
+	}
+	return n, err
+}
+
+// downloadReader is the io.ReadCloser returned by Download.
+type downloadReader struct {
+	conn     net.Conn
+	r        io.Reader
+	n        int64
+	size     int64
+	progress func(transferred, size int64)
+	stop     func()
+}
+
+// Read implements io.Reader.
+func (r *downloadReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.progress != nil && n > 0 {
+		r.progress(r.n, r.size)
+	}
+	if errors.Is(err, io.EOF) && r.n < r.size {
+		// Connection closed before the whole file was received.
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (r *downloadReader) Close() error {
+	r.stop()
+	return r.conn.Close()
+}
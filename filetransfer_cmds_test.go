@@ -0,0 +1,277 @@
+package ts3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ftKeyLen is the length of the keys used by ftServer.
+const ftKeyLen = 8
+
+// ftServer is a stand-in for the TeamSpeak 3 file transfer server.
+// Keys in downloads serve their data, other keys store uploaded data.
+type ftServer struct {
+	net.Listener
+	downloads map[string][]byte
+	wg        sync.WaitGroup
+
+	mtx      sync.Mutex
+	uploaded map[string][]byte
+}
+
+func newFTServer(t *testing.T, downloads map[string][]byte) *ftServer {
+	t.Helper()
+	l, err := newLocalListener()
+	require.NoError(t, err)
+
+	s := &ftServer{Listener: l, downloads: downloads, uploaded: make(map[string][]byte)}
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+func (s *ftServer) Port() int {
+	return s.Addr().(*net.TCPAddr).Port
+}
+
+func (s *ftServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *ftServer) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	key := make([]byte, ftKeyLen)
+	if _, err := io.ReadFull(conn, key); err != nil {
+		return
+	}
+
+	if data, ok := s.downloads[string(key)]; ok {
+		conn.Write(data) //nolint: errcheck
+		return
+	}
+
+	data, _ := io.ReadAll(conn)
+	s.mtx.Lock()
+	s.uploaded[string(key)] = data
+	s.mtx.Unlock()
+}
+
+func (s *ftServer) Uploaded(key string) []byte {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.uploaded[key]
+}
+
+func (s *ftServer) Close() error {
+	err := s.Listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// argRe extracts key value arguments from a command line.
+var argRe = regexp.MustCompile(`(\w+)=(\S*)`)
+
+func lineArgs(line string) map[string]string {
+	args := make(map[string]string)
+	for _, m := range argRe.FindAllStringSubmatch(line, -1) {
+		args[m[1]] = Decode(m[2])
+	}
+	return args
+}
+
+func TestCmdsFileTransfer(t *testing.T) {
+	file := []byte("hello world")
+	ft := newFTServer(t, map[string][]byte{
+		"down0000": file,
+		"down0006": file[6:],
+	})
+	defer func() {
+		assert.NoError(t, ft.Close())
+	}()
+
+	s := newServer(t,
+		handler("ftinitupload", func(line string) string {
+			args := lineArgs(line)
+			switch {
+			case strings.Contains(args["name"], "exists") && args["overwrite"] == "0":
+				return fmt.Sprintf(`clientftfid=%s status=2050 msg=file\salready\sexists`, args["clientftfid"])
+			case args["resume"] == "1":
+				return fmt.Sprintf("clientftfid=%s serverftfid=6 ftkey=upresume port=%d seekpos=6", args["clientftfid"], ft.Port())
+			default:
+				return fmt.Sprintf("clientftfid=%s serverftfid=6 ftkey=upload01 port=%d seekpos=0", args["clientftfid"], ft.Port())
+			}
+		}),
+		handler("ftinitdownload", func(line string) string {
+			args := lineArgs(line)
+			if args["name"] == "/missing" {
+				return `error id=2051 msg=file\snot\sfound`
+			}
+			offset, _ := strconv.Atoi(args["seekpos"])
+			return fmt.Sprintf("clientftfid=%s serverftfid=7 ftkey=down%04d port=%d size=%d", args["clientftfid"], offset, ft.Port(), len(file))
+		}),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	ctx := context.Background()
+
+	upload := func(t *testing.T) {
+		t.Helper()
+		var progress []int64
+		opts := &TransferOptions{Progress: func(n, size int64) {
+			assert.Equal(t, int64(len(file)), size)
+			progress = append(progress, n)
+		}}
+		if !assert.NoError(t, c.Server.Upload(ctx, 1, "/hello.txt", bytes.NewReader(file), int64(len(file)), opts)) {
+			return
+		}
+		assert.Eventually(t, func() bool {
+			return bytes.Equal(file, ft.Uploaded("upload01"))
+		}, time.Second, time.Millisecond*10)
+		if assert.NotEmpty(t, progress) {
+			assert.Equal(t, int64(len(file)), progress[len(progress)-1])
+		}
+	}
+
+	uploadResume := func(t *testing.T) {
+		t.Helper()
+		// Use a reader which isn't an io.Seeker.
+		r := io.MultiReader(bytes.NewReader(file))
+		if !assert.NoError(t, c.Server.Upload(ctx, 1, "/hello.txt", r, int64(len(file)), &TransferOptions{Resume: true})) {
+			return
+		}
+		assert.Eventually(t, func() bool {
+			return string(ft.Uploaded("upresume")) == "world"
+		}, time.Second, time.Millisecond*10)
+	}
+
+	uploadExists := func(t *testing.T) {
+		t.Helper()
+		err := c.Server.Upload(ctx, 1, "/exists.txt", bytes.NewReader(file), int64(len(file)), nil)
+		if assert.Error(t, err) {
+			assert.Equal(t, &Error{ID: 2050, Msg: "file already exists"}, err)
+		}
+
+		assert.NoError(t, c.Server.Upload(ctx, 1, "/exists.txt", bytes.NewReader(file), int64(len(file)), &TransferOptions{Overwrite: true}))
+	}
+
+	uploadShort := func(t *testing.T) {
+		t.Helper()
+		err := c.Server.Upload(ctx, 1, "/hello.txt", bytes.NewReader(file[:4]), int64(len(file)), nil)
+		assert.Error(t, err)
+	}
+
+	download := func(t *testing.T) {
+		t.Helper()
+		rc, err := c.Server.Download(ctx, 1, "/hello.txt", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer func() {
+			assert.NoError(t, rc.Close())
+		}()
+
+		data, err := io.ReadAll(rc)
+		if assert.NoError(t, err) {
+			assert.Equal(t, file, data)
+		}
+	}
+
+	downloadOffset := func(t *testing.T) {
+		t.Helper()
+		var last int64
+		opts := &TransferOptions{Offset: 6, Progress: func(n, size int64) { last = n }}
+		rc, err := c.Server.Download(ctx, 1, "/hello.txt", opts)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer func() {
+			assert.NoError(t, rc.Close())
+		}()
+
+		data, err := io.ReadAll(rc)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "world", string(data))
+		}
+		assert.Equal(t, int64(len(file)), last)
+	}
+
+	downloadMissing := func(t *testing.T) {
+		t.Helper()
+		_, err := c.Server.Download(ctx, 1, "/missing", nil)
+		assert.Error(t, err)
+	}
+
+	downloadCanceled := func(t *testing.T) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(ctx)
+		rc, err := c.Server.Download(ctx, 1, "/hello.txt", nil)
+		if !assert.NoError(t, err) {
+			cancel()
+			return
+		}
+		cancel()
+		assert.Eventually(t, func() bool {
+			_, err := rc.Read(make([]byte, 1))
+			return err != nil && err != io.EOF
+		}, time.Second, time.Millisecond*10)
+		rc.Close() //nolint: errcheck
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"upload", upload},
+		{"upload-resume", uploadResume},
+		{"upload-exists", uploadExists},
+		{"upload-short", uploadShort},
+		{"download", download},
+		{"download-offset", downloadOffset},
+		{"download-missing", downloadMissing},
+		{"download-canceled", downloadCanceled},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestFileTransferHost(t *testing.T) {
+	c := &Client{}
+	assert.NoError(t, FileTransferHost("ft.example.com")(c))
+	assert.Equal(t, "ft.example.com", c.ftHost)
+}
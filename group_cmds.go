@@ -0,0 +1,98 @@
+package ts3
+
+// ClientServerGroup represents a server group membership as returned by ServerGroupsByClientID.
+type ClientServerGroup struct {
+	ID         int    `ms:"sgid"`
+	Name       string `ms:"name"`
+	DatabaseID int    `ms:"cldbid"`
+}
+
+// ServerGroupsByClientID returns the server groups the client identified by cldbid is a member of.
+func (s *ServerMethods) ServerGroupsByClientID(cldbid int) ([]*ClientServerGroup, error) {
+	var groups []*ClientServerGroup
+	if _, err := s.ExecCmd(NewCmd("servergroupsbyclientid").WithArgs(NewArg("cldbid", cldbid)).WithResponse(&groups)); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// ServerGroupMember represents a member of a server group.
+type ServerGroupMember struct {
+	DatabaseID       int    `ms:"cldbid"`
+	Nickname         string `ms:"client_nickname"`
+	UniqueIdentifier string `ms:"client_unique_identifier"`
+}
+
+// ServerGroupClientList returns the members of the server group sgid.
+func (s *ServerMethods) ServerGroupClientList(sgid int) ([]*ServerGroupMember, error) {
+	var members []*ServerGroupMember
+	if _, err := s.ExecCmd(NewCmd("servergroupclientlist").
+		WithArgs(NewArg("sgid", sgid)).
+		WithOptions("-names").
+		WithResponse(&members)); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// ServerGroupAddClient adds the clients identified by cldbids to the server group sgid.
+func (s *ServerMethods) ServerGroupAddClient(sgid int, cldbids ...int) error {
+	_, err := s.ExecCmd(NewCmd("servergroupaddclient").WithArgs(
+		NewArg("sgid", sgid),
+		dbidArgs(cldbids),
+	))
+	return err
+}
+
+// ServerGroupDelClient removes the clients identified by cldbids from the server group sgid.
+func (s *ServerMethods) ServerGroupDelClient(sgid int, cldbids ...int) error {
+	_, err := s.ExecCmd(NewCmd("servergroupdelclient").WithArgs(
+		NewArg("sgid", sgid),
+		dbidArgs(cldbids),
+	))
+	return err
+}
+
+// ClientGetDBIDFromUID returns the database ID of the client identified by uid.
+func (s *ServerMethods) ClientGetDBIDFromUID(uid string) (int, error) {
+	r := struct {
+		DatabaseID int `ms:"cldbid"`
+	}{}
+	_, err := s.ExecCmd(NewCmd("clientgetdbidfromuid").WithArgs(NewArg("cluid", uid)).WithResponse(&r))
+	return r.DatabaseID, err
+}
+
+// dbidArgs returns an ArgGroup of cldbid arguments.
+func dbidArgs(cldbids []int) *ArgGroup {
+	args := make([]CmdArg, len(cldbids))
+	for i, id := range cldbids {
+		args[i] = NewArg("cldbid", id)
+	}
+	return NewArgGroup(args...)
+}
+
+// ChannelGroup represents a virtual server channel group.
+type ChannelGroup struct {
+	ID                int `ms:"cgid"`
+	Name              string
+	Type              int
+	IconID            int
+	Saved             bool `ms:"savedb"`
+	SortID            int
+	NameMode          int
+	ModifyPower       int `ms:"n_modifyp"`
+	MemberAddPower    int `ms:"n_member_addp"`
+	MemberRemovePower int `ms:"n_member_removep"`
+}
+
+// ChannelGroupList returns a list of available channel groups for the selected server.
+func (s *ServerMethods) ChannelGroupList() ([]*ChannelGroup, error) {
+	var groups []*ChannelGroup
+	if _, err := s.ExecCmd(NewCmd("channelgrouplist").WithResponse(&groups)); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
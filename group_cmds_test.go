@@ -0,0 +1,88 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsGroup(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	servergroupsbyclientid := func(t *testing.T) {
+		t.Helper()
+		groups, err := c.Server.ServerGroupsByClientID(19)
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*ClientServerGroup{
+			{ID: 6, Name: "Server Admin", DatabaseID: 19},
+			{ID: 8, Name: "Normal", DatabaseID: 19},
+		}
+		assert.Equal(t, expected, groups)
+	}
+
+	servergroupclientlist := func(t *testing.T) {
+		t.Helper()
+		members, err := c.Server.ServerGroupClientList(6)
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*ServerGroupMember{
+			{DatabaseID: 19, Nickname: "bdeb1337", UniqueIdentifier: "DZhdQU58qyooEK4Fr8Ly738hEmc="},
+			{DatabaseID: 7, Nickname: "MuhChy", UniqueIdentifier: "P8FKaVzyXhtJtD5Uf8t5bNjDpiM="},
+		}
+		assert.Equal(t, expected, members)
+	}
+
+	servergroupaddclient := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ServerGroupAddClient(6, 19, 7))
+	}
+
+	servergroupdelclient := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.ServerGroupDelClient(6, 19))
+	}
+
+	clientgetdbidfromuid := func(t *testing.T) {
+		t.Helper()
+		id, err := c.Server.ClientGetDBIDFromUID("DZhdQU58qyooEK4Fr8Ly738hEmc=")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 19, id)
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"servergroupsbyclientid", servergroupsbyclientid},
+		{"servergroupclientlist", servergroupclientlist},
+		{"servergroupaddclient", servergroupaddclient},
+		{"servergroupdelclient", servergroupdelclient},
+		{"clientgetdbidfromuid", clientgetdbidfromuid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestDBIDArgs(t *testing.T) {
+	assert.Equal(t, "cldbid=1|cldbid=2", dbidArgs([]int{1, 2}).ArgString())
+}
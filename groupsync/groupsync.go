@@ -0,0 +1,355 @@
+// Package groupsync synchronizes TeamSpeak 3 server group memberships with an
+// external source of roles.
+//
+// A Source provides the desired server groups for each client, keyed by the
+// client unique identifier. The Syncer compares them with the memberships of
+// the managed server groups on the selected virtual server and adds or removes
+// memberships so that they match. Groups which are not managed are never
+// changed.
+package groupsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+var (
+	// ErrNoManagedGroups is returned by New if no managed groups are configured.
+	ErrNoManagedGroups = errors.New("no managed groups")
+)
+
+// Source provides the desired server group memberships.
+type Source interface {
+	// DesiredGroups returns the desired server group IDs keyed by client unique identifier.
+	DesiredGroups(ctx context.Context) (map[string][]int, error)
+}
+
+// SourceFunc is an adapter to allow the use of ordinary functions as a Source.
+type SourceFunc func(ctx context.Context) (map[string][]int, error)
+
+// DesiredGroups implements Source.
+func (f SourceFunc) DesiredGroups(ctx context.Context) (map[string][]int, error) {
+	return f(ctx)
+}
+
+// Server is the subset of ts3.ServerMethods used by a Syncer.
+type Server interface {
+	ServerGroupClientList(sgid int) ([]*ts3.ServerGroupMember, error)
+	ServerGroupsByClientID(cldbid int) ([]*ts3.ClientServerGroup, error)
+	ClientGetDBIDFromUID(uid string) (int, error)
+	ServerGroupAddClient(sgid int, cldbids ...int) error
+	ServerGroupDelClient(sgid int, cldbids ...int) error
+}
+
+// Action is the type of a membership change.
+type Action string
+
+const (
+	// Add adds a client to a server group.
+	Add Action = "add"
+
+	// Remove removes a client from a server group.
+	Remove Action = "remove"
+)
+
+// Change is a single membership change.
+type Change struct {
+	Action           Action
+	UniqueIdentifier string
+	DatabaseID       int
+	GroupID          int
+
+	// Applied is true if the change was successfully made on the server.
+	Applied bool
+
+	// Err is the error returned by the server when applying the change.
+	Err error
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%v %v (%v) group %v", c.Action, c.UniqueIdentifier, c.DatabaseID, c.GroupID)
+}
+
+// Report describes the result of a Sync.
+type Report struct {
+	Started  time.Time
+	Finished time.Time
+	DryRun   bool
+
+	// Changes are the changes needed to match the Source, in the order they were applied.
+	Changes []*Change
+
+	// Unresolved are the unique identifiers from the Source which are unknown to the server.
+	Unresolved []string
+}
+
+// Failed returns the changes which failed to apply.
+func (r *Report) Failed() []*Change {
+	var failed []*Change
+	for _, c := range r.Changes {
+		if c.Err != nil {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Syncer synchronizes server group memberships from a Source.
+type Syncer struct {
+	server    Server
+	source    Source
+	managed   map[int]bool
+	dryRun    bool
+	rateLimit time.Duration
+	now       func() time.Time
+}
+
+// ManagedGroups sets the server group IDs which the Syncer manages.
+// Memberships of other groups are never changed.
+func ManagedGroups(ids ...int) func(*Syncer) error {
+	return func(s *Syncer) error {
+		for _, id := range ids {
+			s.managed[id] = true
+		}
+		return nil
+	}
+}
+
+// DryRun configures the Syncer to only report changes without making them.
+func DryRun() func(*Syncer) error {
+	return func(s *Syncer) error {
+		s.dryRun = true
+		return nil
+	}
+}
+
+// RateLimit sets the minimum interval between changes made on the server.
+func RateLimit(interval time.Duration) func(*Syncer) error {
+	return func(s *Syncer) error {
+		s.rateLimit = interval
+		return nil
+	}
+}
+
+// New returns a new Syncer which uses server to synchronize the memberships provided by source.
+// At least one managed group must be configured using ManagedGroups.
+func New(server Server, source Source, options ...func(*Syncer) error) (*Syncer, error) {
+	s := &Syncer{
+		server:  server,
+		source:  source,
+		managed: make(map[int]bool),
+		now:     time.Now,
+	}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(s.managed) == 0 {
+		return nil, ErrNoManagedGroups
+	}
+
+	return s, nil
+}
+
+// Sync compares the memberships from the Source with those on the server and
+// applies the changes required to make them match, unless configured as dry-run.
+//
+// Errors applying individual changes are recorded in the Report and don't
+// stop the synchronization. If ctx is cancelled the partial Report is returned
+// with the context error.
+func (s *Syncer) Sync(ctx context.Context) (*Report, error) {
+	r := &Report{Started: s.now(), DryRun: s.dryRun}
+
+	changes, unresolved, err := s.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.Changes = changes
+	r.Unresolved = unresolved
+
+	if !s.dryRun {
+		if err := s.apply(ctx, changes); err != nil {
+			r.Finished = s.now()
+			return r, err
+		}
+	}
+	r.Finished = s.now()
+
+	return r, nil
+}
+
+// member is the current state of a client.
+type member struct {
+	dbid   int
+	groups map[int]bool
+}
+
+// plan determines the changes needed to match the Source.
+func (s *Syncer) plan(ctx context.Context) ([]*Change, []string, error) {
+	desired, err := s.source.DesiredGroups(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("groupsync: source: %w", err)
+	}
+
+	// Current members of the managed groups by unique identifier.
+	current := make(map[string]*member)
+	for _, sgid := range s.managedGroups() {
+		members, err := s.server.ServerGroupClientList(sgid)
+		if err != nil && !isEmptyResult(err) {
+			return nil, nil, fmt.Errorf("groupsync: server group %v client list: %w", sgid, err)
+		}
+
+		for _, m := range members {
+			cur, ok := current[m.UniqueIdentifier]
+			if !ok {
+				cur = &member{dbid: m.DatabaseID, groups: make(map[int]bool)}
+				current[m.UniqueIdentifier] = cur
+			}
+			cur.groups[sgid] = true
+		}
+	}
+
+	var changes []*Change
+	var unresolved []string
+	uids := make([]string, 0, len(desired))
+	for uid := range desired {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	for _, uid := range uids {
+		cur, ok := current[uid]
+		if !ok {
+			dbid, err := s.server.ClientGetDBIDFromUID(uid)
+			if err != nil {
+				if isEmptyResult(err) || isInvalidClient(err) {
+					unresolved = append(unresolved, uid)
+					continue
+				}
+				return nil, nil, fmt.Errorf("groupsync: client %v: %w", uid, err)
+			}
+			cur = &member{dbid: dbid}
+		}
+
+		// The client list of each group can be stale so confirm
+		// the current groups of the client directly.
+		groups, err := s.server.ServerGroupsByClientID(cur.dbid)
+		if err != nil && !isEmptyResult(err) {
+			return nil, nil, fmt.Errorf("groupsync: client %v server groups: %w", uid, err)
+		}
+		cur.groups = make(map[int]bool, len(groups))
+		for _, g := range groups {
+			if s.managed[g.ID] {
+				cur.groups[g.ID] = true
+			}
+		}
+
+		want := make(map[int]bool)
+		for _, sgid := range desired[uid] {
+			if s.managed[sgid] {
+				want[sgid] = true
+			}
+		}
+
+		changes = append(changes, diff(uid, cur, want)...)
+		delete(current, uid)
+	}
+
+	// Anyone left is no longer wanted in any managed group.
+	for _, uid := range sortedUIDs(current) {
+		changes = append(changes, diff(uid, current[uid], nil)...)
+	}
+
+	return changes, unresolved, nil
+}
+
+// apply makes changes on the server honouring the rate limit.
+func (s *Syncer) apply(ctx context.Context, changes []*Change) error {
+	for i, c := range changes {
+		if i > 0 && s.rateLimit > 0 {
+			t := time.NewTimer(s.rateLimit)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return fmt.Errorf("groupsync: apply: %w", ctx.Err())
+			case <-t.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return fmt.Errorf("groupsync: apply: %w", err)
+		}
+
+		switch c.Action {
+		case Add:
+			c.Err = s.server.ServerGroupAddClient(c.GroupID, c.DatabaseID)
+		case Remove:
+			c.Err = s.server.ServerGroupDelClient(c.GroupID, c.DatabaseID)
+		}
+		c.Applied = c.Err == nil
+	}
+
+	return nil
+}
+
+// managedGroups returns the sorted managed group IDs.
+func (s *Syncer) managedGroups() []int {
+	ids := make([]int, 0, len(s.managed))
+	for id := range s.managed {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// diff returns the changes required to change the groups of cur to want.
+func diff(uid string, cur *member, want map[int]bool) []*Change {
+	var changes []*Change
+	for _, sgid := range sortedGroups(want) {
+		if !cur.groups[sgid] {
+			changes = append(changes, &Change{Action: Add, UniqueIdentifier: uid, DatabaseID: cur.dbid, GroupID: sgid})
+		}
+	}
+	for _, sgid := range sortedGroups(cur.groups) {
+		if !want[sgid] {
+			changes = append(changes, &Change{Action: Remove, UniqueIdentifier: uid, DatabaseID: cur.dbid, GroupID: sgid})
+		}
+	}
+	return changes
+}
+
+func sortedGroups(m map[int]bool) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func sortedUIDs(m map[string]*member) []string {
+	uids := make([]string, 0, len(m))
+	for uid := range m {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// isEmptyResult returns true if err is a TeamSpeak 3 empty result set error.
+func isEmptyResult(err error) bool {
+	return errors.Is(err, ts3.ErrDatabaseEmptyResult)
+}
+
+// isInvalidClient returns true if err is a TeamSpeak 3 invalid client error.
+func isInvalidClient(err error) bool {
+	return errors.Is(err, ts3.ErrClientInvalidID)
+}
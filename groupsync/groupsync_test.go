@@ -0,0 +1,218 @@
+package groupsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is an in memory Server.
+type fakeServer struct {
+	uids    map[string]int       // uid -> cldbid
+	members map[int]map[int]bool // sgid -> cldbid
+	failAdd bool
+	calls   []string
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		uids: map[string]int{
+			"alice": 1,
+			"bob":   2,
+			"carol": 3,
+		},
+		members: map[int]map[int]bool{
+			10: {1: true, 3: true}, // staff
+			11: {2: true},          // subscriber
+			12: {1: true, 2: true}, // unmanaged
+		},
+	}
+}
+
+func (f *fakeServer) uid(cldbid int) string {
+	for uid, id := range f.uids {
+		if id == cldbid {
+			return uid
+		}
+	}
+	return ""
+}
+
+func (f *fakeServer) ServerGroupClientList(sgid int) ([]*ts3.ServerGroupMember, error) {
+	if len(f.members[sgid]) == 0 {
+		return nil, &ts3.Error{ID: int(ts3.ErrDatabaseEmptyResult), Msg: "database empty result set"}
+	}
+
+	var members []*ts3.ServerGroupMember
+	for dbid := range f.members[sgid] {
+		members = append(members, &ts3.ServerGroupMember{DatabaseID: dbid, UniqueIdentifier: f.uid(dbid)})
+	}
+	return members, nil
+}
+
+func (f *fakeServer) ServerGroupsByClientID(cldbid int) ([]*ts3.ClientServerGroup, error) {
+	var groups []*ts3.ClientServerGroup
+	for sgid, members := range f.members {
+		if members[cldbid] {
+			groups = append(groups, &ts3.ClientServerGroup{ID: sgid, DatabaseID: cldbid})
+		}
+	}
+	return groups, nil
+}
+
+func (f *fakeServer) ClientGetDBIDFromUID(uid string) (int, error) {
+	if id, ok := f.uids[uid]; ok {
+		return id, nil
+	}
+	return 0, &ts3.Error{ID: int(ts3.ErrDatabaseEmptyResult), Msg: "database empty result set"}
+}
+
+func (f *fakeServer) ServerGroupAddClient(sgid int, cldbids ...int) error {
+	f.calls = append(f.calls, "add")
+	if f.failAdd {
+		return &ts3.Error{ID: int(ts3.ErrPermissionClientInsufficient), Msg: "insufficient client permissions"}
+	}
+	if f.members[sgid] == nil {
+		f.members[sgid] = make(map[int]bool)
+	}
+	for _, id := range cldbids {
+		f.members[sgid][id] = true
+	}
+	return nil
+}
+
+func (f *fakeServer) ServerGroupDelClient(sgid int, cldbids ...int) error {
+	f.calls = append(f.calls, "remove")
+	for _, id := range cldbids {
+		delete(f.members[sgid], id)
+	}
+	return nil
+}
+
+func staticSource(m map[string][]int) Source {
+	return SourceFunc(func(ctx context.Context) (map[string][]int, error) {
+		return m, nil
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(newFakeServer(), staticSource(nil))
+	assert.Equal(t, ErrNoManagedGroups, err)
+
+	_, err = New(newFakeServer(), staticSource(nil), nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+
+	s, err := New(newFakeServer(), staticSource(nil), ManagedGroups(10, 11))
+	require.NoError(t, err)
+	assert.Equal(t, []int{10, 11}, s.managedGroups())
+}
+
+func TestSync(t *testing.T) {
+	desired := map[string][]int{
+		"alice":   {10, 11, 12}, // 12 is unmanaged so ignored.
+		"bob":     {10},
+		"unknown": {10},
+	}
+
+	expected := []*Change{
+		{Action: Add, UniqueIdentifier: "alice", DatabaseID: 1, GroupID: 11},
+		{Action: Add, UniqueIdentifier: "bob", DatabaseID: 2, GroupID: 10},
+		{Action: Remove, UniqueIdentifier: "bob", DatabaseID: 2, GroupID: 11},
+		{Action: Remove, UniqueIdentifier: "carol", DatabaseID: 3, GroupID: 10},
+	}
+
+	t.Run("dry-run", func(t *testing.T) {
+		srv := newFakeServer()
+		s, err := New(srv, staticSource(desired), ManagedGroups(10, 11), DryRun())
+		require.NoError(t, err)
+
+		r, err := s.Sync(context.Background())
+		require.NoError(t, err)
+		assert.True(t, r.DryRun)
+		assert.Equal(t, expected, r.Changes)
+		assert.Equal(t, []string{"unknown"}, r.Unresolved)
+		assert.Empty(t, srv.calls)
+		assert.Empty(t, r.Failed())
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		srv := newFakeServer()
+		s, err := New(srv, staticSource(desired), ManagedGroups(10, 11))
+		require.NoError(t, err)
+
+		r, err := s.Sync(context.Background())
+		require.NoError(t, err)
+		assert.False(t, r.DryRun)
+		require.Len(t, r.Changes, len(expected))
+		for _, c := range r.Changes {
+			assert.True(t, c.Applied, c.String())
+			assert.NoError(t, c.Err)
+		}
+		assert.Equal(t, map[int]bool{1: true, 2: true}, srv.members[10])
+		assert.Equal(t, map[int]bool{1: true}, srv.members[11])
+		assert.Equal(t, map[int]bool{1: true, 2: true}, srv.members[12])
+
+		// A second run has nothing to do.
+		r, err = s.Sync(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, r.Changes)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		srv := newFakeServer()
+		srv.failAdd = true
+		s, err := New(srv, staticSource(desired), ManagedGroups(10, 11))
+		require.NoError(t, err)
+
+		r, err := s.Sync(context.Background())
+		require.NoError(t, err)
+		failed := r.Failed()
+		assert.Len(t, failed, 2)
+		for _, c := range failed {
+			assert.Equal(t, Add, c.Action)
+			assert.False(t, c.Applied)
+		}
+	})
+
+	t.Run("rate-limit", func(t *testing.T) {
+		srv := newFakeServer()
+		s, err := New(srv, staticSource(desired), ManagedGroups(10, 11), RateLimit(time.Millisecond*10))
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = s.Sync(context.Background())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Millisecond*30))
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		srv := newFakeServer()
+		s, err := New(srv, staticSource(desired), ManagedGroups(10, 11), RateLimit(time.Hour))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		r, err := s.Sync(ctx)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.NotNil(t, r)
+		assert.True(t, r.Changes[0].Applied)
+		assert.False(t, r.Changes[1].Applied)
+	})
+
+	t.Run("source-error", func(t *testing.T) {
+		errSource := errors.New("source failed")
+		src := SourceFunc(func(ctx context.Context) (map[string][]int, error) {
+			return nil, errSource
+		})
+		s, err := New(newFakeServer(), src, ManagedGroups(10))
+		require.NoError(t, err)
+
+		_, err = s.Sync(context.Background())
+		assert.True(t, errors.Is(err, errSource))
+	})
+}
@@ -208,29 +208,39 @@ func decodeSlice(elemType reflect.Type, slice reflect.Value, input map[string]in
 	return nil
 }
 
-var timeType = reflect.TypeOf(time.Time{})
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
 
-// timeHookFunc supports decoding to time.
+// timeHookFunc supports decoding to time and to durations, which the
+// server sends as a number of seconds.
 func timeHookFunc(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
-	// Decode time.Time
-	if to == timeType {
-		var timeInt int64
-
-		switch from.Kind() {
-		case reflect.Int:
-			timeInt = int64(data.(int))
-		case reflect.String:
-			var err error
-			timeInt, err = strconv.ParseInt(data.(string), 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid time %q: %w", data, err)
-			}
-		}
+	if to != timeType && to != durationType {
+		return data, nil
+	}
 
-		if timeInt > 0 {
-			return time.Unix(timeInt, 0), nil
+	var timeInt int64
+	switch from.Kind() {
+	case reflect.Int:
+		timeInt = int64(data.(int))
+	case reflect.String:
+		var err error
+		timeInt, err = strconv.ParseInt(data.(string), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q: %w", data, err)
 		}
 	}
 
+	// Decode time.Duration
+	if to == durationType {
+		return time.Duration(timeInt) * time.Second, nil
+	}
+
+	// Decode time.Time
+	if timeInt > 0 {
+		return time.Unix(timeInt, 0), nil
+	}
+
 	return data, nil
 }
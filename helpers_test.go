@@ -2,6 +2,7 @@ package ts3
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -39,3 +40,15 @@ func TestDecodeResponse(t *testing.T) {
 		NewInvalidResponseError("no lines", input).Error(),
 	)
 }
+
+func TestDecodeResponseTime(t *testing.T) {
+	r := &struct {
+		Created  time.Time
+		Duration time.Duration
+		Timeout  time.Duration
+	}{}
+	assert.NoError(t, DecodeResponse([]string{"created=1259147468 duration=3600 timeout=0"}, r))
+	assert.Equal(t, time.Unix(1259147468, 0), r.Created)
+	assert.Equal(t, time.Hour, r.Duration)
+	assert.Equal(t, time.Duration(0), r.Timeout)
+}
@@ -0,0 +1,303 @@
+package ts3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// iconDir is the directory of the virtual server icon store.
+	iconDir = "/icons/"
+
+	// iconPrefix is the prefix of the name of icon files.
+	iconPrefix = "icon_"
+
+	// iconPerm is the permission which assigns an icon.
+	iconPerm = "i_icon_id"
+)
+
+// IconID returns the ID of the icon image data, which is its CRC32 (IEEE) checksum.
+func IconID(data []byte) int {
+	return int(crc32.ChecksumIEEE(data))
+}
+
+// normalizeIconID converts the signed representation of an icon ID, as used
+// in permission values, to the unsigned representation.
+func normalizeIconID(id int) int {
+	return int(uint32(int32(id)))
+}
+
+// iconPermValue returns the value of the i_icon_id permission for the icon id.
+// The server stores the permission as a signed 32 bit integer.
+func iconPermValue(id int) int {
+	return int(int32(uint32(id)))
+}
+
+// iconPath returns the icon store path of the icon id.
+func iconPath(id int) string {
+	return "/" + iconPrefix + strconv.Itoa(id)
+}
+
+// Icon represents an icon in the virtual server icon store.
+type Icon struct {
+	ID      int
+	Size    int64
+	ModTime time.Time
+}
+
+// IconList returns the icons in the virtual server icon store ordered by ID.
+func (s *ServerMethods) IconList() ([]*Icon, error) {
+	entries, err := s.FTGetFileList(0, "", iconDir)
+	if err != nil {
+		return nil, err
+	}
+
+	icons := make([]*Icon, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir || !strings.HasPrefix(e.Name, iconPrefix) {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimPrefix(e.Name, iconPrefix), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		icons = append(icons, &Icon{ID: int(id), Size: e.Size, ModTime: e.ModTime})
+	}
+	sort.Slice(icons, func(i, j int) bool {
+		return icons[i].ID < icons[j].ID
+	})
+
+	return icons, nil
+}
+
+// IconUpload uploads the icon image data to the virtual server icon store and returns its ID.
+func (s *ServerMethods) IconUpload(ctx context.Context, data []byte) (int, error) {
+	id := IconID(data)
+	if err := s.Upload(ctx, 0, iconPath(id), bytes.NewReader(data), int64(len(data)), &TransferOptions{Overwrite: true}); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// IconDownload returns the image data of the icon id.
+func (s *ServerMethods) IconDownload(ctx context.Context, id int) ([]byte, error) {
+	rc, err := s.Download(ctx, 0, iconPath(normalizeIconID(id)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close() //nolint: errcheck
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("icon download: %w", err)
+	}
+
+	return data, nil
+}
+
+// IconDelete deletes the icons ids from the virtual server icon store.
+func (s *ServerMethods) IconDelete(ids ...int) error {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = iconPath(normalizeIconID(id))
+	}
+
+	return s.FTDeleteFile(0, "", names...)
+}
+
+// IconsInUse returns the set of icon IDs used by the virtual server, its server groups,
+// channel groups, channels and online clients, as well as icons assigned to clients,
+// including offline ones, by the i_icon_id client and channel client permissions.
+func (s *ServerMethods) IconsInUse() (map[int]bool, error) {
+	used := make(map[int]bool)
+	add := func(id int) {
+		if id = normalizeIconID(id); id != 0 {
+			used[id] = true
+		}
+	}
+
+	info, err := s.Info()
+	if err != nil {
+		return nil, err
+	}
+	add(info.IconID)
+
+	groups, err := s.GroupList()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		add(g.IconID)
+	}
+
+	cgroups, err := s.ChannelGroupList()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range cgroups {
+		add(g.IconID)
+	}
+
+	channels, err := s.ChannelList(ChannelIcon)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range channels {
+		add(c.IconID)
+	}
+
+	clients, err := s.ClientList(ClientIcon)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range clients {
+		if c.OnlineClientExt != nil && c.IconID != nil {
+			add(*c.IconID)
+		}
+	}
+
+	ids, err := s.clientIconPerms()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		add(id)
+	}
+
+	return used, nil
+}
+
+// clientIconPerms returns the values of the i_icon_id permission assigned to
+// clients and channel clients. The other assignments are reflected by the
+// icon IDs of the groups and channels.
+func (s *ServerMethods) clientIconPerms() ([]int, error) {
+	assignments, err := s.PermFind(iconPerm)
+	if err != nil {
+		if isEmptyPermResult(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("icons in use: %w", err)
+	}
+
+	var ids []int
+	for _, a := range assignments {
+		var cmd *Cmd
+		switch a.Type {
+		case ClientPermission:
+			cmd = NewCmd("clientpermlist").WithArgs(NewArg("cldbid", a.ID1))
+		case ChannelClientPermission:
+			cmd = NewCmd("channelclientpermlist").WithArgs(NewArg("cid", a.ID1), NewArg("cldbid", a.ID2))
+		default:
+			continue
+		}
+
+		var perms []struct {
+			ID    int `ms:"permid"`
+			Value int `ms:"permvalue"`
+		}
+		if _, err := s.ExecCmd(cmd.WithResponse(&perms)); err != nil {
+			if isEmptyPermResult(err) {
+				continue
+			}
+			return nil, fmt.Errorf("icons in use: %v %d: %w", a.Type, a.ID1, err)
+		}
+
+		for _, p := range perms {
+			if p.ID == a.PermID {
+				ids = append(ids, p.Value)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// isEmptyPermResult returns true if err indicates there are no permissions.
+func isEmptyPermResult(err error) bool {
+	return isEmptyResult(err) || errors.Is(err, ErrPermissionEmptyResult)
+}
+
+// IconDeleteUnused deletes the icons which aren't in use, as determined by IconsInUse,
+// and returns their IDs.
+func (s *ServerMethods) IconDeleteUnused() ([]int, error) {
+	used, err := s.IconsInUse()
+	if err != nil {
+		return nil, err
+	}
+
+	icons, err := s.IconList()
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []int
+	for _, i := range icons {
+		if !used[i.ID] {
+			unused = append(unused, i.ID)
+		}
+	}
+
+	if len(unused) == 0 {
+		return nil, nil
+	}
+
+	if err := s.IconDelete(unused...); err != nil {
+		return nil, err
+	}
+
+	return unused, nil
+}
+
+// SetServerIcon sets the icon of the virtual server.
+func (s *ServerMethods) SetServerIcon(id int) error {
+	return s.Edit(NewArg("virtualserver_icon_id", normalizeIconID(id)))
+}
+
+// SetServerGroupIcon sets the icon of the server group sgid.
+func (s *ServerMethods) SetServerGroupIcon(sgid, id int) error {
+	_, err := s.ExecCmd(NewCmd("servergroupaddperm").WithArgs(
+		NewArg("sgid", sgid),
+		iconPermArgs(id),
+	))
+	return err
+}
+
+// SetChannelGroupIcon sets the icon of the channel group cgid.
+func (s *ServerMethods) SetChannelGroupIcon(cgid, id int) error {
+	_, err := s.ExecCmd(NewCmd("channelgroupaddperm").WithArgs(
+		NewArg("cgid", cgid),
+		iconPermArgs(id),
+	))
+	return err
+}
+
+// SetChannelIcon sets the icon of the channel cid.
+func (s *ServerMethods) SetChannelIcon(cid, id int) error {
+	_, err := s.ExecCmd(NewCmd("channeladdperm").WithArgs(
+		NewArg("cid", cid),
+		NewArg("permsid", iconPerm),
+		NewArg("permvalue", iconPermValue(id)),
+	))
+	return err
+}
+
+// iconPermArgs returns the arguments to assign the i_icon_id permission for the icon id to a group.
+func iconPermArgs(id int) *ArgSet {
+	return NewArgSet(
+		NewArg("permsid", iconPerm),
+		NewArg("permvalue", iconPermValue(id)),
+		NewArg("permnegated", false),
+		NewArg("permskip", false),
+	)
+}
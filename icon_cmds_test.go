@@ -0,0 +1,168 @@
+package ts3
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIconID(t *testing.T) {
+	data := []byte("\x89PNG icon data")
+	assert.Equal(t, int(crc32.ChecksumIEEE(data)), IconID(data))
+
+	// Icon IDs are reported as signed 32 bit integers by some commands.
+	assert.Equal(t, 3000000000, normalizeIconID(-1294967296))
+	assert.Equal(t, 500, normalizeIconID(500))
+	assert.Equal(t, -1294967296, iconPermValue(3000000000))
+	assert.Equal(t, 500, iconPermValue(500))
+}
+
+func TestCmdsIcon(t *testing.T) {
+	icon := []byte("\x89PNG icon data")
+	ft := newFTServer(t, map[string][]byte{"icondown": icon})
+	defer func() {
+		assert.NoError(t, ft.Close())
+	}()
+
+	var deleted string
+	s := newServer(t,
+		handler("ftgetfilelist", func(line string) string {
+			if lineArgs(line)["path"] != "/icons/" {
+				return `error id=2051 msg=file\snot\sfound`
+			}
+			return `cid=0 path=\/icons\/ name=icon_500 size=10 datetime=1691527133 type=1|name=icon_3000000000 size=12 datetime=1691527133 type=1|name=icon_123 size=14 datetime=1691527200 type=1|name=icon_100 size=11 datetime=1691527133 type=1|name=icon_777 size=13 datetime=1691527133 type=1|name=icon_2000 size=9 datetime=1691527133 type=1|name=other.txt size=1 datetime=1691527133 type=1`
+		}),
+		handler("permissionlist", func(string) string {
+			return `permid=133 permname=i_client_talk_power|permid=145 permname=i_icon_id`
+		}),
+		handler("permfind", func(line string) string {
+			if lineArgs(line)["permid"] != "145" {
+				return `error id=2563 msg=permission\sempty\sresult`
+			}
+			return `t=0 id1=6 id2=0 p=145|t=1 id1=20 id2=0 p=145|t=4 id1=499 id2=21 p=145|t=1 id1=22 id2=0 p=145`
+		}),
+		handler("clientpermlist", func(line string) string {
+			if lineArgs(line)["cldbid"] != "20" {
+				return `error id=1281 msg=database\sempty\sresult\sset`
+			}
+			return `cldbid=20 permid=133 permvalue=50 permnegated=0 permskip=0|permid=145 permvalue=123 permnegated=0 permskip=0`
+		}),
+		handler("channelclientpermlist", func(line string) string {
+			return `cid=499 cldbid=21 permid=145 permvalue=2000 permnegated=0 permskip=0`
+		}),
+		handler("ftdeletefile", func(line string) string {
+			deleted = line
+			return ""
+		}),
+		handler("ftinitupload", func(line string) string {
+			args := lineArgs(line)
+			if args["name"] != fmt.Sprintf("/icon_%d", IconID(icon)) || args["overwrite"] != "1" {
+				return `error id=2050 msg=invalid\sname`
+			}
+			return fmt.Sprintf("clientftfid=%s serverftfid=6 ftkey=iconupld port=%d seekpos=0", args["clientftfid"], ft.Port())
+		}),
+		handler("ftinitdownload", func(line string) string {
+			args := lineArgs(line)
+			if args["name"] != "/icon_3000000000" {
+				return `error id=2051 msg=file\snot\sfound`
+			}
+			return fmt.Sprintf("clientftfid=%s serverftfid=7 ftkey=icondown port=%d size=%d", args["clientftfid"], ft.Port(), len(icon))
+		}),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	ctx := context.Background()
+
+	iconlist := func(t *testing.T) {
+		t.Helper()
+		icons, err := c.Server.IconList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*Icon{
+			{ID: 100, Size: 11, ModTime: time.Unix(1691527133, 0)},
+			{ID: 123, Size: 14, ModTime: time.Unix(1691527200, 0)},
+			{ID: 500, Size: 10, ModTime: time.Unix(1691527133, 0)},
+			{ID: 777, Size: 13, ModTime: time.Unix(1691527133, 0)},
+			{ID: 2000, Size: 9, ModTime: time.Unix(1691527133, 0)},
+			{ID: 3000000000, Size: 12, ModTime: time.Unix(1691527133, 0)},
+		}
+		assert.Equal(t, expected, icons)
+	}
+
+	iconupload := func(t *testing.T) {
+		t.Helper()
+		id, err := c.Server.IconUpload(ctx, icon)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, IconID(icon), id)
+		assert.Eventually(t, func() bool {
+			return string(ft.Uploaded("iconupld")) == string(icon)
+		}, time.Second, time.Millisecond*10)
+	}
+
+	icondownload := func(t *testing.T) {
+		t.Helper()
+		data, err := c.Server.IconDownload(ctx, -1294967296)
+		if assert.NoError(t, err) {
+			assert.Equal(t, icon, data)
+		}
+	}
+
+	iconsinuse := func(t *testing.T) {
+		t.Helper()
+		used, err := c.Server.IconsInUse()
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[int]bool{100: true, 123: true, 500: true, 2000: true, 3000000000: true}, used)
+		}
+	}
+
+	icondeleteunused := func(t *testing.T) {
+		t.Helper()
+		ids, err := c.Server.IconDeleteUnused()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []int{777}, ids)
+		assert.Equal(t, `ftdeletefile cid=0 cpw= name=\/icon_777`, deleted)
+	}
+
+	seticon := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.SetServerIcon(3000000000))
+		assert.NoError(t, c.Server.SetServerGroupIcon(2, 3000000000))
+		assert.NoError(t, c.Server.SetChannelGroupIcon(5, 3000000000))
+		assert.NoError(t, c.Server.SetChannelIcon(499, 3000000000))
+		assert.NoError(t, c.SetIcon(3000000000))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"iconlist", iconlist},
+		{"iconupload", iconupload},
+		{"icondownload", icondownload},
+		{"iconsinuse", iconsinuse},
+		{"icondeleteunused", icondeleteunused},
+		{"seticon", seticon},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
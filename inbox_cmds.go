@@ -0,0 +1,97 @@
+package ts3
+
+import "time"
+
+// Message represents an offline message in the inbox of the query client.
+type Message struct {
+	ID        int       `ms:"msgid"`
+	SenderUID string    `ms:"cluid"`
+	Subject   string    `ms:"subject"`
+	Body      string    `ms:"message"` // Only populated by MessageGet.
+	Read      bool      `ms:"flag_read"`
+	Created   time.Time `ms:"timestamp"`
+}
+
+// MessageList returns the offline messages in the inbox of the query client.
+// The message bodies aren't included, use MessageGet to retrieve them.
+func (s *ServerMethods) MessageList() ([]*Message, error) {
+	var msgs []*Message
+	if _, err := s.ExecCmd(NewCmd("messagelist").WithResponse(&msgs)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// MessageAdd sends an offline message to the client identified by uid.
+func (s *ServerMethods) MessageAdd(uid, subject, body string) error {
+	_, err := s.ExecCmd(NewCmd("messageadd").WithArgs(
+		NewArg("cluid", uid),
+		NewArg("subject", subject),
+		NewArg("message", body),
+	))
+	return err
+}
+
+// MessageGet returns the offline message msgid including its body.
+// Retrieving a message doesn't mark it as read, use MessageUpdateFlag to do so.
+func (s *ServerMethods) MessageGet(msgid int) (*Message, error) {
+	msg := &Message{}
+	if _, err := s.ExecCmd(NewCmd("messageget").WithArgs(NewArg("msgid", msgid)).WithResponse(msg)); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// MessageDel deletes the offline message msgid.
+func (s *ServerMethods) MessageDel(msgid int) error {
+	_, err := s.ExecCmd(NewCmd("messagedel").WithArgs(NewArg("msgid", msgid)))
+	return err
+}
+
+// MessageUpdateFlag sets the read flag of the offline message msgid.
+func (s *ServerMethods) MessageUpdateFlag(msgid int, read bool) error {
+	_, err := s.ExecCmd(NewCmd("messageupdateflag").WithArgs(
+		NewArg("msgid", msgid),
+		NewArg("flag", read),
+	))
+	return err
+}
+
+// DeliverMessage delivers a message to the client identified by uid.
+// If the client is online, the message is sent as a private text message to each
+// of its connections, otherwise it's stored as an offline message.
+// It returns true if the client was online.
+func (s *ServerMethods) DeliverMessage(uid, subject, body string) (bool, error) {
+	clients, err := s.ClientList(ClientUID)
+	if err != nil {
+		return false, err
+	}
+
+	text := body
+	if subject != "" {
+		text = subject + "\n" + body
+	}
+
+	var online bool
+	for _, c := range clients {
+		if c.OnlineClientExt == nil || c.UniqueIdentifier == nil || *c.UniqueIdentifier != uid {
+			continue
+		}
+
+		online = true
+		if err := s.SendTextMessage(TargetClient, c.ID, text); err != nil {
+			return true, err
+		}
+	}
+
+	if online {
+		return true, nil
+	}
+
+	return false, s.MessageAdd(uid, subject, body)
+}
@@ -0,0 +1,115 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsInbox(t *testing.T) {
+	var empty bool
+	s := newServer(t, handler("messagelist", func(string) string {
+		if empty {
+			return `error id=1281 msg=database\sempty\sresult\sset`
+		}
+		return commands["messagelist"]
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	messagelist := func(t *testing.T) {
+		t.Helper()
+		msgs, err := c.Server.MessageList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*Message{
+			{ID: 1, SenderUID: "DZhdQU58qyooEK4Fr8Ly738hEmc=", Subject: "Hello", Created: time.Unix(1691527133, 0)},
+			{ID: 2, SenderUID: "P8FKaVzyXhtJtD5Uf8t5bNjDpiM=", Subject: "Re: Hello", Read: true, Created: time.Unix(1691527200, 0)},
+		}
+		assert.Equal(t, expected, msgs)
+	}
+
+	messagelistempty := func(t *testing.T) {
+		t.Helper()
+		empty = true
+		defer func() { empty = false }()
+		msgs, err := c.Server.MessageList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Nil(t, msgs)
+	}
+
+	messageget := func(t *testing.T) {
+		t.Helper()
+		msg, err := c.Server.MessageGet(1)
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := &Message{
+			ID:        1,
+			SenderUID: "DZhdQU58qyooEK4Fr8Ly738hEmc=",
+			Subject:   "Hello",
+			Body:      "How are you?",
+			Created:   time.Unix(1691527133, 0),
+		}
+		assert.Equal(t, expected, msg)
+	}
+
+	messageadd := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.MessageAdd("P8FKaVzyXhtJtD5Uf8t5bNjDpiM=", "Hello", "How are you?"))
+	}
+
+	messagedel := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.MessageDel(1))
+	}
+
+	messageupdateflag := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.MessageUpdateFlag(1, true))
+	}
+
+	delivermessage := func(t *testing.T) {
+		t.Helper()
+		online, err := c.Server.DeliverMessage("DZhdQU58qyooEK4Fr8Ly738hEmc=", "Reminder", "Event starts soon")
+		if assert.NoError(t, err) {
+			assert.True(t, online)
+		}
+
+		online, err = c.Server.DeliverMessage("P8FKaVzyXhtJtD5Uf8t5bNjDpiM=", "Reminder", "Event starts soon")
+		if assert.NoError(t, err) {
+			assert.False(t, online)
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"messagelist", messagelist},
+		{"messagelistempty", messagelistempty},
+		{"messageget", messageget},
+		{"messageadd", messageadd},
+		{"messagedel", messagedel},
+		{"messageupdateflag", messageupdateflag},
+		{"delivermessage", delivermessage},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
@@ -0,0 +1,230 @@
+package ts3
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const (
+	// BindingVoice can be passed to BindingList to list the voice bindings.
+	BindingVoice = "voice"
+	// BindingQuery can be passed to BindingList to list the server query bindings.
+	BindingQuery = "query"
+	// BindingFileTransfer can be passed to BindingList to list the file transfer bindings.
+	BindingFileTransfer = "filetransfer"
+)
+
+// HostInfo represents the instance wide statistics returned by HostInfo.
+type HostInfo struct {
+	Uptime                        time.Duration `ms:"instance_uptime"`
+	Timestamp                     time.Time     `ms:"host_timestamp_utc"`
+	ServersRunning                int           `ms:"virtualservers_running_total"`
+	TotalMaxClients               int           `ms:"virtualservers_total_maxclients"`
+	TotalClientsOnline            int           `ms:"virtualservers_total_clients_online"`
+	TotalChannelsOnline           int           `ms:"virtualservers_total_channels_online"`
+	FileTransferBandwidthSent     uint64        `ms:"connection_filetransfer_bandwidth_sent"`
+	FileTransferBandwidthReceived uint64        `ms:"connection_filetransfer_bandwidth_received"`
+	FileTransferTotalSent         uint64        `ms:"connection_filetransfer_bytes_sent_total"`
+	FileTransferTotalReceived     uint64        `ms:"connection_filetransfer_bytes_received_total"`
+	PacketsSentTotal              uint64        `ms:"connection_packets_sent_total"`
+	PacketsReceivedTotal          uint64        `ms:"connection_packets_received_total"`
+	BytesSentTotal                uint64        `ms:"connection_bytes_sent_total"`
+	BytesReceivedTotal            uint64        `ms:"connection_bytes_received_total"`
+	BandwidthSentLastSecond       uint64        `ms:"connection_bandwidth_sent_last_second_total"`
+	BandwidthReceivedLastSecond   uint64        `ms:"connection_bandwidth_received_last_second_total"`
+	BandwidthSentLastMinute       uint64        `ms:"connection_bandwidth_sent_last_minute_total"`
+	BandwidthReceivedLastMinute   uint64        `ms:"connection_bandwidth_received_last_minute_total"`
+}
+
+// HostInfo returns the uptime, client totals and bandwidth statistics of the instance.
+func (s *ServerMethods) HostInfo() (*HostInfo, error) {
+	r := &HostInfo{}
+	if _, err := s.ExecCmd(NewCmd("hostinfo").WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// InstanceProperties are the instance properties which can be changed by InstanceEdit.
+// Only non nil properties are changed.
+type InstanceProperties struct {
+	GuestServerQueryGroup       *int    `ms:"serverinstance_guest_serverquery_group"`
+	TemplateServerAdminGroup    *int    `ms:"serverinstance_template_serveradmin_group"`
+	TemplateServerDefaultGroup  *int    `ms:"serverinstance_template_serverdefault_group"`
+	TemplateChannelAdminGroup   *int    `ms:"serverinstance_template_channeladmin_group"`
+	TemplateChannelDefaultGroup *int    `ms:"serverinstance_template_channeldefault_group"`
+	FileTransferPort            *int    `ms:"serverinstance_filetransfer_port"`
+	MaxTotalDownloadBandwidth   *uint64 `ms:"serverinstance_max_download_total_bandwidth"`
+	MaxTotalUploadBandwidth     *uint64 `ms:"serverinstance_max_upload_total_bandwidth"`
+	ServerQueryFloodCommands    *int    `ms:"serverinstance_serverquery_flood_commands"`
+	ServerQueryFloodTime        *int    `ms:"serverinstance_serverquery_flood_time"`
+	ServerQueryBanTime          *int    `ms:"serverinstance_serverquery_ban_time"`
+	PendingConnectionsPerIP     *int    `ms:"serverinstance_pending_connections_per_ip"`
+}
+
+// args returns the arguments for the non nil properties.
+func (p *InstanceProperties) args() []CmdArg {
+	var args []CmdArg
+	v := reflect.ValueOf(p).Elem()
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); !f.IsNil() {
+			args = append(args, NewArg(t.Field(i).Tag.Get("ms"), f.Elem().Interface()))
+		}
+	}
+
+	return args
+}
+
+// InstanceEdit changes the instance configuration using the non nil properties of p.
+func (s *ServerMethods) InstanceEdit(p *InstanceProperties) error {
+	args := p.args()
+	if len(args) == 0 {
+		return errors.New("instance edit: no properties")
+	}
+
+	_, err := s.ExecCmd(NewCmd("instanceedit").WithArgs(args...))
+	return err
+}
+
+// BindingList returns the IP addresses used by the instance for the subsystem,
+// one of BindingVoice, BindingQuery or BindingFileTransfer.
+// If subsystem is empty the voice bindings are returned.
+func (s *ServerMethods) BindingList(subsystem string) ([]string, error) {
+	cmd := NewCmd("bindinglist")
+	if subsystem != "" {
+		cmd.WithArgs(NewArg("subsystem", subsystem))
+	}
+
+	var r []struct {
+		IP string
+	}
+	if _, err := s.ExecCmd(cmd.WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	ips := make([]string, len(r))
+	for i, b := range r {
+		ips[i] = b.IP
+	}
+
+	return ips, nil
+}
+
+// ServerProcessStop shuts down the TeamSpeak 3 server instance, logging the reason
+// if not empty. The connection will be closed by the server.
+func (s *ServerMethods) ServerProcessStop(reason string) error {
+	cmd := NewCmd("serverprocessstop")
+	if reason != "" {
+		cmd.WithArgs(NewArg("reasonmsg", reason))
+	}
+
+	_, err := s.ExecCmd(cmd)
+	return err
+}
+
+// QueryLogin represents a server query login.
+type QueryLogin struct {
+	DatabaseID int    `ms:"cldbid"`
+	ServerID   int    `ms:"sid"`
+	LoginName  string `ms:"client_login_name"`
+	Password   string `ms:"client_login_password"` // Password is only returned by QueryLoginAdd.
+}
+
+// QueryLoginAdd creates a server query login with the name for the client cldbid
+// and returns it including the generated password. If cldbid is 0 the login
+// is created for the current query client on the selected virtual server.
+func (s *ServerMethods) QueryLoginAdd(name string, cldbid int) (*QueryLogin, error) {
+	args := []CmdArg{NewArg("client_login_name", name)}
+	if cldbid != 0 {
+		args = append(args, NewArg("cldbid", cldbid))
+	}
+
+	r := &QueryLogin{}
+	if _, err := s.ExecCmd(NewCmd("queryloginadd").WithArgs(args...).WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// QueryLoginDel deletes the server query login of the client cldbid.
+func (s *ServerMethods) QueryLoginDel(cldbid int) error {
+	_, err := s.ExecCmd(NewCmd("querylogindel").WithArgs(NewArg("cldbid", cldbid)))
+	return err
+}
+
+// QueryLoginList returns the server query logins whose name matches pattern,
+// which may contain % as a wildcard. If pattern is empty all logins are returned.
+func (s *ServerMethods) QueryLoginList(pattern string) ([]*QueryLogin, error) {
+	cmd := NewCmd("queryloginlist")
+	if pattern != "" {
+		cmd.WithArgs(NewArg("pattern", pattern))
+	}
+
+	var logins []*QueryLogin
+	if _, err := s.ExecCmd(cmd.WithResponse(&logins)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return logins, nil
+}
+
+// QueryLoginRotate replaces the server query login of the client cldbid with a
+// new one of the same name and the same scope, and returns it including the new
+// password. The virtual server of the login, or none for an instance wide login,
+// is selected while the login is replaced and the previous selection restored.
+//
+// The login is deleted before the new one is added, so if adding fails the
+// returned error wraps ErrQueryLoginDeleted and the client has no login.
+func (s *ServerMethods) QueryLoginRotate(cldbid int) (login *QueryLogin, err error) {
+	logins, err := s.QueryLoginList("")
+	if err != nil {
+		return nil, err
+	}
+
+	var old *QueryLogin
+	for _, l := range logins {
+		if l.DatabaseID == cldbid {
+			old = l
+			break
+		}
+	}
+	if old == nil {
+		return nil, fmt.Errorf("query login rotate: no login for client %d", cldbid)
+	}
+
+	info, err := s.Whoami()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.ServerID != old.ServerID {
+		if err = s.Use(old.ServerID); err != nil {
+			return nil, fmt.Errorf("query login rotate: use %d: %w", old.ServerID, err)
+		}
+
+		defer func() {
+			// Restore the previously selected server
+			if err2 := s.Use(info.ServerID); err2 != nil && err == nil {
+				err = err2
+			}
+		}()
+	}
+
+	if err = s.QueryLoginDel(cldbid); err != nil {
+		return nil, err
+	}
+
+	if login, err = s.QueryLoginAdd(old.LoginName, cldbid); err != nil {
+		return nil, fmt.Errorf("query login rotate: %q: %w: %w", old.LoginName, ErrQueryLoginDeleted, err)
+	}
+
+	return login, nil
+}
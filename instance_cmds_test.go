@@ -0,0 +1,203 @@
+package ts3
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdsInstance(t *testing.T) {
+	var lines []string
+	var failAdd bool
+	record := func(cmd string) serverOption {
+		return handler(cmd, func(line string) string {
+			lines = append(lines, line)
+			return commands[cmd]
+		})
+	}
+	s := newServer(t,
+		record("instanceedit"),
+		record("bindinglist"),
+		record("serverprocessstop"),
+		handler("queryloginadd", func(line string) string {
+			lines = append(lines, line)
+			if failAdd {
+				return `error id=513 msg=nickname\sis\salready\sin\suse`
+			}
+			return commands["queryloginadd"]
+		}),
+		record("use"),
+		record("querylogindel"),
+		record("queryloginlist"),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	hostinfo := func(t *testing.T) {
+		t.Helper()
+		hi, err := c.Server.HostInfo()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := &HostInfo{
+			Uptime:                      1903 * time.Second,
+			Timestamp:                   time.Unix(1499948005, 0),
+			ServersRunning:              1,
+			TotalMaxClients:             32,
+			TotalClientsOnline:          2,
+			TotalChannelsOnline:         3,
+			FileTransferTotalSent:       617,
+			PacketsSentTotal:            926413,
+			PacketsReceivedTotal:        650335,
+			BytesSentTotal:              92911395,
+			BytesReceivedTotal:          61940731,
+			BandwidthSentLastSecond:     81,
+			BandwidthReceivedLastSecond: 83,
+			BandwidthSentLastMinute:     92,
+			BandwidthReceivedLastMinute: 88,
+		}
+		assert.Equal(t, expected, hi)
+	}
+
+	instanceedit := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		port := 30034
+		bw := uint64(1024)
+		err := c.Server.InstanceEdit(&InstanceProperties{FileTransferPort: &port, MaxTotalUploadBandwidth: &bw})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{"instanceedit serverinstance_filetransfer_port=30034 serverinstance_max_upload_total_bandwidth=1024"}, lines)
+
+		assert.Error(t, c.Server.InstanceEdit(&InstanceProperties{}))
+	}
+
+	bindinglist := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		ips, err := c.Server.BindingList(BindingQuery)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{"0.0.0.0", "::"}, ips)
+
+		_, err = c.Server.BindingList("")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{"bindinglist subsystem=query", "bindinglist"}, lines)
+	}
+
+	serverprocessstop := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		if !assert.NoError(t, c.Server.ServerProcessStop("maintenance window")) {
+			return
+		}
+		assert.Equal(t, []string{`serverprocessstop reasonmsg=maintenance\swindow`}, lines)
+	}
+
+	queryloginadd := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		l, err := c.Server.QueryLoginAdd("orchestrator", 19)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, &QueryLogin{DatabaseID: 19, ServerID: 1, LoginName: "orchestrator", Password: "+r7ZwY3n"}, l)
+		assert.Equal(t, []string{"queryloginadd client_login_name=orchestrator cldbid=19"}, lines)
+	}
+
+	queryloginlist := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		logins, err := c.Server.QueryLoginList("orch%")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*QueryLogin{
+			{DatabaseID: 19, ServerID: 1, LoginName: "orchestrator"},
+			{DatabaseID: 20, LoginName: "serveradmin"},
+		}
+		assert.Equal(t, expected, logins)
+		assert.Equal(t, []string{"queryloginlist pattern=orch%"}, lines)
+	}
+
+	querylogindel := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		if !assert.NoError(t, c.Server.QueryLoginDel(19)) {
+			return
+		}
+		assert.Equal(t, []string{"querylogindel cldbid=19"}, lines)
+	}
+
+	queryloginrotate := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		l, err := c.Server.QueryLoginRotate(19)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "+r7ZwY3n", l.Password)
+		// The login is replaced on its own virtual server.
+		assert.Equal(t, []string{
+			"queryloginlist",
+			"use sid=1",
+			"querylogindel cldbid=19",
+			"queryloginadd client_login_name=orchestrator cldbid=19",
+			"use sid=18",
+		}, lines)
+
+		// Instance wide logins are replaced without a virtual server selected.
+		lines = nil
+		_, err = c.Server.QueryLoginRotate(20)
+		assert.NoError(t, err)
+		assert.Equal(t, "use sid=0", lines[1])
+
+		failAdd = true
+		defer func() { failAdd = false }()
+		lines = nil
+		_, err = c.Server.QueryLoginRotate(19)
+		assert.True(t, errors.Is(err, ErrQueryLoginDeleted))
+		assert.True(t, errors.Is(err, ErrClientNicknameInUse))
+		assert.Equal(t, "use sid=18", lines[len(lines)-1])
+
+		_, err = c.Server.QueryLoginRotate(99)
+		if assert.Error(t, err) {
+			assert.True(t, strings.Contains(err.Error(), "no login"))
+		}
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"hostinfo", hostinfo},
+		{"instanceedit", instanceedit},
+		{"bindinglist", bindinglist},
+		{"serverprocessstop", serverprocessstop},
+		{"queryloginadd", queryloginadd},
+		{"queryloginlist", queryloginlist},
+		{"querylogindel", querylogindel},
+		{"queryloginrotate", queryloginrotate},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.f(t)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package ts3
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogTimeLayout is the layout of the time of a server log entry.
+const LogTimeLayout = "2006-01-02 15:04:05.999999"
+
+// LogLevel is the level of an entry written with LogAdd.
+type LogLevel int
+
+const (
+	// LogError is the error log level.
+	LogError LogLevel = 1
+
+	// LogWarning is the warning log level.
+	LogWarning LogLevel = 2
+
+	// LogDebug is the debug log level.
+	LogDebug LogLevel = 3
+
+	// LogInfo is the info log level.
+	LogInfo LogLevel = 4
+)
+
+// String implements fmt.Stringer.
+func (l LogLevel) String() string {
+	switch l {
+	case LogError:
+		return "ERROR"
+	case LogWarning:
+		return "WARNING"
+	case LogDebug:
+		return "DEBUG"
+	case LogInfo:
+		return "INFO"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(l))
+	}
+}
+
+// LogEntry represents a server log entry.
+type LogEntry struct {
+	Time     time.Time
+	Level    string // Level is the level, e.g. INFO, of the entry.
+	Channel  string // Channel is the component, e.g. VirtualServer or Query, which logged the entry.
+	ServerID int    // ServerID is the virtual server the entry relates to, zero for instance entries.
+	Message  string
+
+	// Raw is the unparsed entry.
+	Raw string
+}
+
+// ParseLogEntry parses a server log line of the form:
+//
+//	2017-06-26 21:55:30.307009|INFO    |Query         |   |query from 47 [::1]:63629 issued: login
+//
+// Lines which don't match the format are returned with only Message and Raw set.
+func ParseLogEntry(line string) *LogEntry {
+	e := &LogEntry{Message: line, Raw: line}
+
+	parts := strings.SplitN(line, "|", 5)
+	if len(parts) != 5 {
+		return e
+	}
+
+	t, err := time.ParseInLocation(LogTimeLayout, strings.TrimSpace(parts[0]), time.UTC)
+	if err != nil {
+		return e
+	}
+
+	e.Time = t
+	e.Level = strings.TrimSpace(parts[1])
+	e.Channel = strings.TrimSpace(parts[2])
+	e.ServerID, _ = strconv.Atoi(strings.TrimSpace(parts[3]))
+	e.Message = parts[4]
+
+	return e
+}
+
+// LogQuery represents the parameters of LogView.
+type LogQuery struct {
+	// Lines is the number of lines to return, between 1 and 100, zero for the server default.
+	Lines int
+
+	// Reverse returns the newest entries first, starting at the end of the log
+	// unless BeginPos is set.
+	Reverse bool
+
+	// Instance returns entries from the instance log instead of the virtual server log.
+	Instance bool
+
+	// BeginPos is the position in the log file to start reading from, zero for the server default.
+	// It's used to page through the log using the LastPos of the previous page.
+	BeginPos int
+}
+
+// LogPage is a page of entries returned by LogView.
+type LogPage struct {
+	// LastPos is the position in the log file to use as LogQuery.BeginPos to
+	// retrieve the next page, zero if there are no more entries.
+	LastPos int
+
+	// FileSize is the size of the log file.
+	FileSize int
+
+	// Entries are the log entries, in the order returned by the server.
+	Entries []*LogEntry
+}
+
+// LogView returns a page of entries from the server log.
+func (s *ServerMethods) LogView(q *LogQuery) (*LogPage, error) {
+	args := []CmdArg{NewArg("reverse", q.Reverse), NewArg("instance", q.Instance)}
+	if q.Lines > 0 {
+		args = append(args, NewArg("lines", q.Lines))
+	}
+	if q.BeginPos > 0 {
+		args = append(args, NewArg("begin_pos", q.BeginPos))
+	}
+
+	var r []struct {
+		LastPos  int    `ms:"last_pos"`
+		FileSize int    `ms:"file_size"`
+		Line     string `ms:"l"`
+	}
+	if _, err := s.ExecCmd(NewCmd("logview").WithArgs(args...).WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	page := &LogPage{}
+	if len(r) > 0 {
+		// Only the first record includes the cursor details.
+		page.LastPos = r[0].LastPos
+		page.FileSize = r[0].FileSize
+	}
+
+	for _, l := range r {
+		if l.Line != "" {
+			page.Entries = append(page.Entries, ParseLogEntry(l.Line))
+		}
+	}
+
+	return page, nil
+}
+
+// LogAdd writes msg to the server log with the given level.
+func (s *ServerMethods) LogAdd(level LogLevel, msg string) error {
+	_, err := s.ExecCmd(NewCmd("logadd").WithArgs(
+		NewArg("loglevel", int(level)),
+		NewArg("logmsg", msg),
+	))
+	return err
+}
+
+// Tail polls the server log, or the instance log if instance is true, every interval
+// and sends new entries to the returned entries channel.
+//
+// Only entries logged after Tail is called are sent. Polling stops when ctx
+// is done or an error occurs, in which case the error is sent to the returned
+// error channel. Both channels are closed when polling stops.
+//
+// Tail polls using the client of s, so commands executed by other callers
+// are interleaved with the polls. As the server log is that of the selected
+// virtual server, callers which change it with Use while tailing should give
+// Tail a dedicated Client.
+func (s *ServerMethods) Tail(ctx context.Context, interval time.Duration, instance bool) (<-chan *LogEntry, <-chan error) {
+	entries := make(chan *LogEntry)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(entries)
+
+		if err := s.tail(ctx, interval, instance, entries); err != nil {
+			errc <- err
+		}
+	}()
+
+	return entries, errc
+}
+
+// tail implements Tail.
+//
+// The server pages through the log backwards from the end, so each poll reads
+// pages in reverse until it reaches the end of the log at the previous poll,
+// pos, and then sends the entries which start at or after pos in log order.
+func (s *ServerMethods) tail(ctx context.Context, interval time.Duration, instance bool, entries chan<- *LogEntry) error {
+	page, err := s.LogView(&LogQuery{Lines: 1, Reverse: true, Instance: instance})
+	if err != nil {
+		return err
+	}
+	pos := page.FileSize
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if page, err = s.LogView(&LogQuery{Lines: 100, Reverse: true, Instance: instance}); err != nil {
+			return err
+		}
+
+		size := page.FileSize
+		switch {
+		case size == pos:
+			// No new entries.
+			continue
+		case size < pos:
+			// The log was replaced, only follow entries written from now on.
+			pos = size
+			continue
+		}
+
+		// Read back to pos, each page ends where the one after it begins.
+		var newer []*LogEntry
+		end := size
+		for {
+			newer = append(logEntriesFrom(page.Entries, end, pos), newer...)
+			if page.LastPos <= pos || page.LastPos >= end {
+				break
+			}
+
+			end = page.LastPos
+			if page, err = s.LogView(&LogQuery{Lines: 100, Reverse: true, Instance: instance, BeginPos: end}); err != nil {
+				return err
+			}
+		}
+		pos = size
+
+		for _, e := range newer {
+			select {
+			case entries <- e:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// logEntriesFrom returns the entries, which end at the log position end, that
+// start at or after the log position pos.
+func logEntriesFrom(entries []*LogEntry, end, pos int) []*LogEntry {
+	i := len(entries)
+	for i > 0 {
+		// Each entry is a line terminated by a newline.
+		end -= len(entries[i-1].Raw) + 1
+		if end < pos {
+			break
+		}
+		i--
+	}
+
+	return entries[i:]
+}
@@ -0,0 +1,245 @@
+package ts3
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdsLog(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	logview := func(t *testing.T) {
+		t.Helper()
+		page, err := c.Server.LogView(&LogQuery{Lines: 2, Reverse: true, BeginPos: 411980})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 403788, page.LastPos)
+		assert.Equal(t, 411980, page.FileSize)
+		if !assert.Len(t, page.Entries, 2) {
+			return
+		}
+
+		e := page.Entries[0]
+		assert.Equal(t, time.Date(2017, 6, 26, 21, 55, 30, 307009000, time.UTC), e.Time)
+		assert.Equal(t, "INFO", e.Level)
+		assert.Equal(t, "Query", e.Channel)
+		assert.Equal(t, 0, e.ServerID)
+		assert.Equal(t, "query from 47 [::1]:63629 issued: login with account 'serveradmin'(serveradmin)", e.Message)
+
+		e = page.Entries[1]
+		assert.Equal(t, "WARNING", e.Level)
+		assert.Equal(t, "VirtualServer", e.Channel)
+		assert.Equal(t, 1, e.ServerID)
+		assert.Equal(t, "client disconnected", e.Message)
+	}
+
+	logadd := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.LogAdd(LogInfo, "backup completed"))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"logview", logview},
+		{"logadd", logadd},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+// fakeLog is a log file served by logview, which like the server pages
+// backwards from begin_pos, or the end of the file, when reverse is set.
+type fakeLog struct {
+	mtx   sync.Mutex
+	lines []string
+}
+
+func (l *fakeLog) add(msgs ...string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for _, m := range msgs {
+		l.lines = append(l.lines, "2017-06-26 21:55:30.000000|INFO    |Query         |   |"+m)
+	}
+}
+
+func (l *fakeLog) logview(line string) string {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	args := lineArgs(line)
+	if args["reverse"] != "1" {
+		return `error id=1538 msg=invalid\sparameter`
+	}
+
+	offsets := make([]int, len(l.lines)+1)
+	for i, line := range l.lines {
+		offsets[i+1] = offsets[i] + len(line) + 1
+	}
+	size := offsets[len(l.lines)]
+
+	end := len(l.lines)
+	if v, ok := args["begin_pos"]; ok {
+		pos, _ := strconv.Atoi(v)
+		for offsets[end] > pos {
+			end--
+		}
+	}
+	lines, _ := strconv.Atoi(args["lines"])
+	start := end - lines
+	if start < 0 {
+		start = 0
+	}
+
+	recs := []string{fmt.Sprintf("last_pos=%d file_size=%d", offsets[start], size)}
+	for i, line := range l.lines[start:end] {
+		if i == 0 {
+			recs[0] += " l=" + encoder.Replace(line)
+			continue
+		}
+		recs = append(recs, "l="+encoder.Replace(line))
+	}
+
+	return strings.Join(recs, "|")
+}
+
+func TestTail(t *testing.T) {
+	log := &fakeLog{}
+	log.add("old1", "old2")
+
+	var polls int
+	s := newServer(t, handler("logview", func(line string) string {
+		// Entries are logged between the polls.
+		polls++
+		switch polls {
+		case 3:
+			log.add("first")
+		case 5:
+			var msgs []string
+			for i := 0; i < 150; i++ {
+				msgs = append(msgs, fmt.Sprintf("entry %d", i))
+			}
+			log.add(msgs...)
+		}
+		return log.logview(line)
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expected := []string{"first"}
+	for i := 0; i < 150; i++ {
+		expected = append(expected, fmt.Sprintf("entry %d", i))
+	}
+
+	entries, errc := c.Server.Tail(ctx, time.Millisecond*10, false)
+	var msgs []string
+	for e := range entries {
+		msgs = append(msgs, e.Message)
+		if len(msgs) == len(expected) {
+			cancel()
+		}
+	}
+	assert.NoError(t, <-errc)
+	assert.Equal(t, expected, msgs)
+}
+
+func TestTailConcurrent(t *testing.T) {
+	s := newServer(t, handler("logview", func(line string) string {
+		return "last_pos=0 file_size=100"
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entries, errc := c.Server.Tail(ctx, time.Millisecond, false)
+
+	// Commands run alongside the polls must receive their own responses.
+	for i := 0; i < 100; i++ {
+		v, err := c.Version()
+		require.NoError(t, err)
+		assert.Equal(t, "3.0.12.2", v.Version)
+	}
+
+	cancel()
+	for range entries {
+	}
+	assert.NoError(t, <-errc)
+}
+
+func TestTailError(t *testing.T) {
+	s := newServer(t, handler("logview", func(string) string {
+		return `error id=1539 msg=parameter\snot\sfound`
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	entries, errc := c.Server.Tail(context.Background(), time.Millisecond*10, true)
+	for range entries {
+	}
+	assert.Error(t, <-errc)
+}
+
+func TestParseLogEntry(t *testing.T) {
+	e := ParseLogEntry("not a log line")
+	assert.Equal(t, &LogEntry{Message: "not a log line", Raw: "not a log line"}, e)
+
+	e = ParseLogEntry("bad time|INFO|Query||msg")
+	assert.True(t, e.Time.IsZero())
+	assert.Equal(t, "bad time|INFO|Query||msg", e.Message)
+
+	e = ParseLogEntry("2017-06-26 21:55:30.307009|INFO    |Query         |   |a|b")
+	assert.Equal(t, "a|b", e.Message)
+}
+
+func TestLogLevelString(t *testing.T) {
+	assert.Equal(t, "INFO", LogInfo.String())
+	assert.Equal(t, "unknown (9)", LogLevel(9).String())
+}
@@ -0,0 +1,116 @@
+package ts3
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the maximum length, in bytes, of a text message accepted by the server.
+// Longer messages are split into multiple messages by the send methods.
+const MaxMessageLength = 1024
+
+// TargetMode is the type of target a text message is sent to.
+type TargetMode int
+
+const (
+	// TargetClient sends a private message to a client, the target is the client ID.
+	TargetClient TargetMode = 1
+
+	// TargetChannel sends a message to the channel the query client is currently in.
+	TargetChannel TargetMode = 2
+
+	// TargetServer sends a message to the virtual server.
+	TargetServer TargetMode = 3
+)
+
+// SendTextMessage sends msg to target using mode.
+// For TargetChannel and TargetServer target is ignored by the server, use
+// SendChannelMessage to message a channel the query client isn't in.
+// Messages longer than MaxMessageLength are split into multiple messages.
+func (s *ServerMethods) SendTextMessage(mode TargetMode, target int, msg string) error {
+	for _, m := range splitMessage(msg, MaxMessageLength) {
+		if _, err := s.ExecCmd(NewCmd("sendtextmessage").WithArgs(
+			NewArg("targetmode", int(mode)),
+			NewArg("target", target),
+			NewArg("msg", m),
+		)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendChannelMessage sends msg to the channel cid.
+// If the query client isn't in cid it is moved there, using the channel password cpw
+// if needed, for the duration of the send and then moved back to its original channel.
+func (s *ServerMethods) SendChannelMessage(cid int, cpw, msg string) (err error) {
+	info, err := s.Whoami()
+	if err != nil {
+		return err
+	}
+
+	if info.ClientChannelID != cid {
+		if err = s.ClientMove(cid, cpw, info.ClientID); err != nil {
+			return err
+		}
+
+		defer func() {
+			// Restore the original channel.
+			if err2 := s.ClientMove(info.ClientChannelID, "", info.ClientID); err2 != nil && err == nil {
+				err = err2
+			}
+		}()
+	}
+
+	return s.SendTextMessage(TargetChannel, cid, msg)
+}
+
+// GlobalMessage sends msg to all clients on all virtual servers of the instance.
+// Messages longer than MaxMessageLength are split into multiple messages.
+func (c *Client) GlobalMessage(msg string) error {
+	for _, m := range splitMessage(msg, MaxMessageLength) {
+		if _, err := c.ExecCmd(NewCmd("gm").WithArgs(NewArg("msg", m))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitMessage splits msg into parts of at most max bytes.
+// Where possible parts are split at whitespace and never in the middle of a rune.
+func splitMessage(msg string, max int) []string {
+	if len(msg) <= max {
+		return []string{msg}
+	}
+
+	var parts []string
+	for len(msg) > max {
+		i := max
+		for i > 0 && !utf8.RuneStart(msg[i]) {
+			i--
+		}
+		if i == 0 {
+			// Invalid UTF-8, split at the limit.
+			i = max
+		}
+
+		// Prefer to split at the last whitespace in the second half of the part.
+		if j := strings.LastIndexFunc(msg[:i], unicode.IsSpace); j > i/2 {
+			i = j
+		}
+
+		if p := strings.TrimRightFunc(msg[:i], unicode.IsSpace); p != "" {
+			parts = append(parts, p)
+		}
+		msg = strings.TrimLeftFunc(msg[i:], unicode.IsSpace)
+	}
+
+	if msg != "" {
+		parts = append(parts, msg)
+	}
+
+	return parts
+}
@@ -0,0 +1,87 @@
+package ts3
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsMessage(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	sendtextmessage := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.SendTextMessage(TargetClient, 42087, "hello world"))
+		assert.NoError(t, c.Server.SendTextMessage(TargetServer, 0, strings.Repeat("long message ", 200)))
+	}
+
+	sendchannelmessage := func(t *testing.T) {
+		t.Helper()
+		// Query client is in the channel.
+		assert.NoError(t, c.Server.SendChannelMessage(432, "", "hello channel"))
+		// Query client is moved there and back.
+		assert.NoError(t, c.Server.SendChannelMessage(499, "secret", "hello other channel"))
+	}
+
+	gm := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.GlobalMessage("server restart in 5 minutes"))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"sendtextmessage", sendtextmessage},
+		{"sendchannelmessage", sendchannelmessage},
+		{"gm", gm},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      string
+		max      int
+		expected []string
+	}{
+		{"short", "hello", 10, []string{"hello"}},
+		{"exact", "0123456789", 10, []string{"0123456789"}},
+		{"whitespace", "hello big world", 10, []string{"hello big", "world"}},
+		{"no-whitespace", "0123456789abcdef", 10, []string{"0123456789", "abcdef"}},
+		{"early-whitespace", "a 123456789abcdef", 10, []string{"a 12345678", "9abcdef"}},
+		{"multibyte", "ääääää", 5, []string{"ää", "ää", "ää"}},
+		{"invalid-utf8", "\x80\x80\x80\x80", 2, []string{"\x80\x80", "\x80\x80"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, splitMessage(tc.msg, tc.max))
+		})
+	}
+
+	long := strings.Repeat("€uro ", 1000)
+	for _, p := range splitMessage(long, MaxMessageLength) {
+		assert.LessOrEqual(t, len(p), MaxMessageLength)
+		assert.True(t, utf8.ValidString(p))
+	}
+}
@@ -48,7 +48,76 @@ var commands = map[string]string{
 	"clientdblist":         "cldbid=7 client_unique_identifier=DZhdQU58qyooEK4Fr8Ly738hEmc= client_nickname=MuhChy client_created=1259147468 client_lastconnected=1259421233",
 	"whoami":               "virtualserver_status=online virtualserver_id=18 virtualserver_unique_identifier=gNITtWtKs9+Uh3L4LKv8\\/YHsn5c= virtualserver_port=9987 client_id=94 client_channel_id=432 client_nickname=serveradmin\\sfrom\\s127.0.0.1:49725 client_database_id=1 client_login_name=serveradmin client_unique_identifier=serveradmin client_origin_server_id=0",
 	"serversnapshotcreate": `version=3 data=KLUv\/aTFeAEAjeAAOuOELE2wkhEbvGpFNp3\/lL6F\/QtsvQL+1czMVGFGZESk9Io3xQ0eQdv35ihoaUFN+NNTCDtDIBmwWyMj4LXst++WmZmZmWZIrYrZKf0p\/SmdIboCtQLbAv2iNEl\/Rqd7cNZIc8m6Mo\/pGca0lt\/yTxkmEWXe37pcMOnJ56Z1VNqahbP+FN+adXFN41X2WEz2W4wXdS7KRtTDPJVt+bv1GtuctVDCGeF+bTEw3qnr6pJ19b5NiHE3X\/SSL2qStIUr\/frfmtHnTX8mvWTe+YVJ+rqozbuFnSvAPijKro4z37yquJayf8GPaVJM59nFM+xFn62nuCbpz9Jn1\/7J4lJF48Nc1WfRV7zPqFubZHFMYZWLKNwtfinVEb9qN6R4snn+nCZZNcRRDyGjhLC+KL+GwNU3HPVqh7Bc1AnhIiuEsziKEcI9Au6OXoZrIaBzMMIwHCL7\/VmIzTsFcLqLViHBKSV9Ecb36HPwO9c6Fu580wVQmgZNJyAAnAhAzrQRLS45w69J38EVH9FSXbmATXM8FQA7Fe+MaBnRYoCG9e4SydgdKtuk7C0LT845+Ca2OSehe5FL19yjLTxJFO5XlzS+KMtzaWlavNLYRR1MXeotC7vkF2HblXWkTm14vx7RokWv4ekpvt8NHR8UaKDp2uWiVZwUBVYkUJLygmVsE4mCBUnnQpQKJqKcwHBOXKTAsCCJfKWky53CcTHhdMjCTeeCmp7NeVmBT7CgTEwVFAawiYkKx+HeHCvRYucFCQuTEpyYiVmZFCueksliYEVfJ1CBFiwnVPKKifkyTqCUSIFRYmpZBoOTYTgmOKdjosPhfIdDwilBiTKOsZh\/iV5flWRdvGMxWbZeyq8vWiOU9r76Iv0df5Z1dnk0DWMwRlq3nlNZBq4oCMGHDWz4MIDWckCNHohI8EMJFvggAAk5AHFRFOYlou9gTfvqqytT7\/kggx5B6CgyowMCmxoR2PiQoCb6ClsjEWfrW0kMipcVCnDrZeiUFis0SotIYWWgsKLs+ebdes2l32Jwa5Oyh8leo\/DWMw4T3KL0CJMyUUoxAPLJQeRm1v1UO\/1xfpVQpnuCHJavVgjGSyc31cmDhRoYIPghABFRnRD\/23HCTzWFdYKmGTOmmFqw48OCjZoJ42Q3hQ\/+qw\/CN1vI0klCd3ISekmlnvKVD9Sg8bEBDZVTxwm+Xac3IzTtOauE4hQdlG101+38XoSvhhtsaOhxI1+Wlrrpvaf3hGLUc04RQtFuvV\/9CB74IYQWbi4QMtNBDDQ9drC5gcjHRhEgICih5oMiNZlrG8oQITMcQHCTw4QbHD6gwUDIjAki3NzQ4BPEBq0Ux\/nu89voXkJO\/9VY2qkjBjQ+Qyr46CBCE4SHHR7U6FADTYzysaTZ1g1tnW9920JPP3mjnReHGjQ\/OKiJ9+mXd352gq6bZN0PQ\/FmerG0tMWF1unpvSlf66BpPgtdmyUELb2QtDFHVykcMmRGpQR0Dr8N8btpn47Q3nuC5tNJQ\/TJv\/tV52ANINzQGOHGhgygxfhOS9\/dOEPb0gmqlUpo0q5CEMb9dnyPPhnRQOMzwY0aFnT0CMIBl33GmJYbmDGixaVjjGfvYnH9nVecghbHm+sUZbwQ3dVCkUIJ6bsr9O7jTCXuFNbe2iSNvyi7esZbisnWd0zx6jULY2pnr7MrhuIV3g96UUznFJ5RdMFgFAnESOBFn5hkNle9yBhPMS5qZVakdfGO8SxrrzPHrv7CkJkaMHx47DATOYy+Ea3z0wxJWz+iaZjK0ndwdnVNchbvbBQWRR5dnZC8WGB7Uk5cUOaWSJF58ooMFpQYMiooy8oJGbyDYq8ocVErKR4XjkVJlmHhiCiRMXE7lUV3EtMkyyaqE7cCM2DVjgIu0hIVMHWBj9mcuvLVL+tfri51LJ7xV9h6ydyKUj7t4tHJaO\/U\/Mq0haMY\/P2p1xJWfX99VS\/ST03YeqlrnJq0V2a6q3KthsKSuFSL4qE2Zb7AckVHGNF2kPIirs5SQONa2ZaYHBaKYtYSKmVeiZCC9VGvnrhKE6h0xw6bFpNyMEuiQ1kY4WZLjpMnJImNNGD4lNAoI2NKFBMZX1wwTi8u9FTomSA8WWFR1LuCDxivxIRQThFxOvFexnGPBLtvxbLVBBQ+lcaYMIZLS+LGGVpLhD\/LLr9lXblrmFKJRjRJW2+fs5fO+1PE9mWoTnaCpLYSik839O\/s\/SepCRWWFSUoTEhMoKOmBRs1OACx4aGBTQk4bKyUnz\/POOveGXI4T1C91ELoyaofph9PEHKzgwc3PTa4+fFCDTVuZsDxQYHIzI0RZHxIpdyYVvgPOUgv9NNJjCH9sNvYTQcrsEHzgJAZC4B8PvhxU6ULEdAEsYFGx5CZF0e0WCAa9AgiCA8ZRAeniyulD0f3KMXQ3FpC02oNWazxjnf+KDLDg0iNDx1qaNxgo7VQYMHn0SE+PhVg8PGB43Mjg88MN2zmiJZ64uCUdN+ZnaWYUsg5GSNkaZ77afkAkM8OCWyA+PiMAEKNjyIzroUhNjYksLnR4xNkB5sSitBskIHNhSNa6IgWCR28MWQmCA8p+PhogMNMDDg+U7pHacbT3lNG6O2ZrYRmniRE30RxrJXFoyLFywutwmQBEVBzGGBR8vIsNhcXLDEuKoD0oAGigw2RB2x47PBB1tfPTVtr\/TFCn\/9Ce8oSetBGaD88oY7RPiBAcAgBogMc9dk36awPvq95Q5JSC719pYOmae1H6651D2Oc5fu4IazdhXB9r6GLpZbyrfPAkLicqMRKhWV7Ji\/VxEqLBDNpcTnJVFUUFgIENiNgQLPDkBoVdlz3Lr06fp3gky9LC8k4\/UtI22ffproDo1JJseKC5ERFyfUsJylYixRZCg\/Jk7CA0QIMn7v1c7jOPbWGrKQTdCvc2EJ6ihvfN6FJjEY9LzhySiCGCJL+IiNx4iKa9bZOGZ17Dkbq5K1URltdpTDaaN+U0kZnHYTw3vumjc\/dZ6171jn4oKTw0knldbZaeN+D5q2SOktvlfVK6VykNtpLH4XUQhfBFz3NR6qD1975XqRuQvtilHTKOkcshHBCOzKBjpoaQvjU2EFDJ3TTQQels1XGERNq1OQgMjH1mZTUwQhrrY7WOa2dk1IaIa1Tyvmcq5Fe5+C89FY4oY3RXQrvvXU++t51VF5XYb3TwTeDdiatOZ+hwSmlffEHReGqVilrdZLmyNEjCOqg1Vo0x7auorVsKhPCK62F3E01hww0MdSwgU46C6O001JaWZBo8WR9V84P3LPw5CZ01MUJZbXPWeum4466yeeIDx4+4PBjhrYe2zCNu3i0TeMV709p\/pnkuVyklz5bWAa2+IVZm9YYu2bFtqmSYrKr58+41jxXNlXY+kzzvnnnop73K8Mm3V8ujL\/Fqb0v6zUZMDzDoIp3jifLM1l4qq5sSy984pr2mdb4+1VZnit7mCvmJfOuufSra7\/F+Cz71zY8QZiDGe4YBrtN0t66qMy1xlL2U\/XVlFiMxjaJa1yC91kZbul80k3qIPTTtGmcv\/QvmXOS2hcK955dGq8krm3VZ37BaBt2uXTu4dVrmuKzelzHXksSx1nnu5Xh7MKq752LuvrWswvTOhbvfeursrbsu8Y7n0kxm\/fL+uyyOlyy+AWjc1EdbOEq5l84hvtTr1\/btMbY1TPcterq2qYtvqVJT7prnJrhOm+5COMv2ot+ozCM569tSVOkR2iA4VMCCDQj2gxJW4\/iykBdV+wzMZ+MJOGJK9A9GSj44Jedeyidu0JiN+jhUj0K0GwyrwU7aKqMNd4XeFVehvmoo5iTUimFzMzIAIAgBADj0gAUFooapKmehoHZAMOAQBAcA8ZAUBAUGgXGZyAgAABAgABAAIIABACEIAhCohwww9oApxt1YJlRFykMPOU2dtuqo3xKQWksdgwT49jtnjMl7fNOc0Xf4zcqLpOyhMohy3mtuZzbHD2ovVdzfRCZn\/uFA96acPjLOYsevfcxJ1iw6C7uPQtx2IVCOscIt\/8it\/mTEZutFGgudjgWwFwkuVmAXyywBWNx\/I5dYIMNQ0iLU4URMmcxXUH+WjNgeflXoTNEIsDBLCatdPg7ceFt8qQl7ufJ9wyB\/Pm6bfgPuzRgnU1YfItHQrPEP8bfVECqTwLQuGSSq1t\/QLkadd3vl7kPcW7iVaZ91yCdA3B2kYqA\/oqoyPlAnn8TAFo5FeErUwynb96jSya6SoYKF8mjhKiF\/YJJCP6k7oRQOcHix9o2Quw0Zbl5yQbzraYZrm93gfU+TdPWZLqRID8QrQpBYXTUAamRRhgKJkRu1ioQlowEP56lYNmTt9sKRqkDtFiYXQji7GmNH36xkOGLiqbg88UvYCNh9lQK9kKwzYAF76RK07VyE7xSBBiCnxK10bMEzf9ny5uySqX99WitBlIh45G2CeYDBfY0k7SMMiU3kaQL08zoWelm5ARQrj2mxpxNnBG1HmZuT0cJjvD+7zSpVElFevbI1dzKkHW80Tw6AlpKh5NtlUbakx7Yl9gDVHWl9C79pNxbiDC9FJKBuEuPWHUuhy9Hnj2FHMHwCBUh5pu+GOWymWAqbMnm8tIaWyJFeGa0ubTJfNGObXOuMDgpVvXJjOrTXWLzKF5NVWcktY7kuVS4n1UbsPVWqglU0Re9mOsI7OMgMGYEWqlbhq77+FZTguOoSZQx0\/lOtosBvfibQh+VqNFobqn94F7qE\/frojFfQXq0K9uriRemqDpspkLu1qFiINZXJUtvQ16x\/pHOlPBHcKCQJiFeS5RZEDRwOM6eQIyQ\/WZKE8+avxtSl6NuYo1O87aqrGLCozEYVZCtiHC9FhF84MsuFerWDtO7Ptxh3RVXr3B8huJCMRcU\/BN\/70PMXzR6KN2yeEVznZfyyrFfXlHajiO6xF+k74NUaDbmyg0Z4sGfFgVkdA+p64XZO+Z+42td31jL328VWbQv8oodsV4T6ODQrn0vFVAHsLWiPI\/14O88MItGDEBF6diAs+HuTDLgDgv7REvA9Plm5M6f1KcmLJWxB618JyyMCiMPhQHsc1BBsY+Hyv9COuGDphWkbbB9zsEGU\/RU2nrcve0IaNwTuMBKluBpJrWMIpxvwFQCcijbcMAKRbmgR+NjDl0f0DOsuc6rpws6+hi9PjL19NYgWK8Ttd+TM6KVAKiiq81\/ZPpBR1pbh6Rdft1IRpuXzkem9wz8T3EvInqIx0eIkMXoWzdbCdkv8l+fCCR7El9e39YHLxFj6n+2UJ2nyhVV8miQ8SCeGi0V3HfgK9sojWFitAOWpIvX4XpWkskSy8CbhoJ2g9sNk3s6zX+tie2Hu\/V914A\/wZxJ31xHM3ApY+ZlEsCN+8MQPcyxuqEUpkpq5nJ6UcWg1Jc1c8CVJf+QBqlydevWcsn\/iHxcz22B+Rf7bN9o0dQLNlDfY8GfJbibAKGKA197\/oRlZ0WFF5+rWOyV000nOVJRAPbFp0QpSvlfgrJSSpVDDPaNd0qcQidFKp3rN7HC9asmmYzwygSMX4SASaFWNuAZg1XSA6SwUGnmljAAfYu4DjQ5UkQYEZBIwrOmHLIs7BNDQk3p14vD4\/4gA3pBW1mgz8dktPlWm\/zSg1pM+QKfMwiuIe1Qn4yoTt+gg95UaN4y2IyjajT2Vj3vBpli+zLsnaYKcjh8rz0G7GnenNU4A+NM7hHAEEdG+2WsyRiej9dGoXLIHEwiNRVqfjHkrgP2FnZlPKhFAUVNWI2QAwsuliuLxuLgKJN8wvySwTuEBwl+wUarUZ7lCvQPc98f0qnn9JD2LDx2e6YOe4u11j6sQRJChsX6OzIJMPDv36K4MGEWTydVArgC0GJ8vG0fKsgCf4NuCOWfOFdw3nHZqzNxByU\/OvfbRDZgvg3m5413Kx9C\/5XxLwL\/GlxSUO754afz+hHwe\/r8Rfp6AO7cBhxAZI7CM1Cag0DkG5VPmwFpAjYxJiOTA5r603UgDgB7hYT1I2ic\/ONPxoT+8+d2vwsQw8b0D41pPB6B8W3dpG3P+IM1Ut\/pb8o788klBmrvD3biX9im9PfXNNlcoJ34mTctcc1fXIIk8o\/qRvBniheJ7czQviM4nkAHGPvzpNZd37rPebGOZO5eTuvTy6SbGzMGCmQ+LuozvzhievL6bjWJ3PfogI7P78f7i19R13glvqFMr+HIh9CY+MMCj95ssN\/Fn2jhW\/+SgXmbf4Lf7q3lxOwVT6Y3bvzlRvF2+zWKsW0yMMtem\/sFhJw9TVv7qx8\/7Oxm3QlyvTor1\/PB8aD1fWi6J\/0w1bmmtURqk1ag3K2caQNEOHp3\/6fbo2eH6VgvN31CM38P+08vPXfpDHvt8zcHRFbNmw5WZoPlhQpTH5IvvmyVQ\/\/jD868OPBj+UW2bzgokCejY+34VvAUBRtvoGpMJyF9lhzZKyUrqZIXSyStfZBR+tCQch+USW8HbtbZToZTz37ALGTdYkXxdm52woAREh0PQ2wRxrZ2CY7UWkYGoXiP9Ew9sZz2Gkeaetee0IiMtPC\/3wREmbGLOChh3e5JPDFJFNw1zKSoBPsGDxJLmp1HHmIzjyMPbGMvsKNJmCJyLtsmFWISGn3qga3wwzC4k70NpuUJbDPCsAocVYYyDP+nlNGzAoj9KAycl6i00PIFXysjDZiz2B9KGJgLQ2CvZVDb+EAEFhGA31xOYEtdGuViADl8EmWXYdT0Jfpr6xf11dXHQv5zuSegw21WOyIjjaLd3t3DMm2C6kFAI+OTnjOleJdWOsBWgH1f4oFkLswvkhFZdkVM03FVUE6hId0pH8xepENyWjMmRNx5+Bh1HbhshE3MP5UR45gxfhG5CL7wX2SI5wlmK32TABSwtBkHUxUy9UWhUCeWQB8BJDAwKHvEL4YxG4\/xOkyQwXpUzGFSAR2DE9cnYjUzhnCTq\/CXFp2XOTuNI\/i5zQsxvABSmfcpqqQ66IZ\/R30CZ\/ycEukjsTSweUWXyZ6YCljLtcdrxcK2UWBTg2pJVqh0vTFy9HUK72UA1yBui1IjOCltTDRS8uFqC+aHWIegrdeTqBxImCK\/dYzfZkVZRHB+43+kL1Gpi0MUBKTxgPBBlKgQmKT8Z2Kl+Sir38Q1mJM+jiPOpTNfUKfvD7GAyVZPUCkDGFN6NBnZfJdVy+JShf9MUA4xrDUwoMOuEZ2K\/CcpmWEIaCTQGM5Rj8U0Tthj\/iRFYbrP8w9Z+bLZAJUIeWsP4hCV9NCipGBjqoeQuGmU+O6E+SkXzjaTW07wbNg6uwcWMOl7d2jWUDUPA2Mn\/AV4MPP6Ibm95QeGKnsu\/1bBjRRNlQlCNZIZYOwcHLKmMz\/iW2kp6oX\/aNcuncn7MlLPnCn3mjJ5zOiBaJeLjQFscIFFwjuN5biU+JJEKJvLkgkG6\/I0r2UQD+oJ4sy\/qkQ\/SysQAc0JxZwiPgPLg1YNDmvhAOmw8Iz3cFgW4yEyqmOcCfQcCRRmm+kmlIyFEc+fFzFITMkHIPCQwEMiJozrIBIR73cyoJ697TtIKvF+KCC4MfMXDx\/b+4euzuN+YyUDBYzK+9YW3HyeGi1V0ZDH\/nCPeFCh7SdziEhb1b1RSYnmCFhiJfzp7UmUnvH8iJKArW4msJUAyIdQVAU4yr0p6SrbDcc8DANAEjTlqJEdZG02RRQGLK5y5Pknjzycs9TXDrqcGVHqosNKY8umxZaDaHG2IMHbC6buu\/rL1CesCzg534okAXrJoa\/cxHMaMEpbXYalnZyuZuC5wiaUB3Is0wxWmtC61L5IvOtesQxJFHrTFB3jQBVIDCYsIC5yRZVpiU2RO0HKwhhVVMnLZGHqOFXGjiJ4EY6bnBM0\/U\/XhQkwFLi9pqHFnrZgNjxCVYg7NPLcAVMYfTHXGwZViDxojozzizwGgADoei6TJ+Zf0IGKcWwDRux1AEf6QgzUsFz1rwggzDyZytjzkxu6GzI1XKjkG3IYHRZs2joOdoJSBqhJDnjoga2U9oMrfgubriJcI9V2aEC+LNFDkiEJhYuI43NMEgQGzywwqWojyYhkTCvqYiAebcqCrv0zMnEL+XL\/JgMG0TJiMghnu6FSQ+PqbhEdMsLcBkNg+Z7jLSfBqDDZchQaP61s0BQBQKvj1ugFK1JB1IbDLAS3k4LIg9usUsCHPGaWt+5aAC6O0asc9CarOXjl5hbEM41d\/lWRoQTKe9KQMSUXQiyzB1fx+XBDEvZOC2QEFtWebrxWABr1wPz41kham2epuhIwBI7HDMn3LtKhE1Jro1nWtZBBGuOLCCt9OqfdsK0WKy2D5pNe9GSha5FLKvHAM82oq1X53ck92SMXkmqg4Gr3cdiCNQHWps2m0s7hKWtLxjkkG1v5FBSNLizOUCp+ExjyjeisB3NFFBFBzNt1vxs8HIrFPccaShG2Nq5vTlwc+LfYHFGeiuqHkMKTnC7G8FRynfdzQEo0RNYpnJcsFPVrWRGB8nKifWwcYnP6VrW1+ziHaVNMQVH1I7JTUtBNNojl3DMsD4ZtrRgkiqul7CrQ1uEbkGC6hJDW1HxyOk+UjPgwXkT6TPsnMnerbVaDm2KecFGoRqI3kMKdozq6+VZ0HVi1fgbgnYoodOseijhO91ZH1TboqCYGjABstnOHNzJKjw9tGdxr0iS0DTwxAoQQ6VPBmm0sQpY7RFm8wPZGKej+ukqzdYyoce+2tgF7WiLiTw8K9IpWzf0pl2umb13OG6B714ynLuma4tacQAvIxR1O2TX6i7G0VxLPviVRcg0KMhzWkIk7QNU5sRqBmaSB0vQjuHSis6FbSdGJVesaOwlAJ3dZg7LtKOAYMu28Wqc5KB1nLpNTM9FXCyd0IFhbf9bs8qfFKSbDaEEPLFJpD+60Jn8cuKgihX+B6tbg3BiYHhadZy3NbnMozUmr7f22CVhatA2ZxcTUsShYOEIuyPWRQtdi5ayqORz5HF1C16NO55rgboChvYMQxRiBlr4b8uWU6YWnyp++w51f4o+dGzqORnhTs+1uRDyVBslITwCNJ3qIZmEYbWCPIr+nCUbo62CjGfVDdoDnAzDkPxNdrd571cjm6AAdzAB+JUyOwEq0sjywUWfBNtGNyQuOZqAnhdiz+qxTVY+qXtOU7T6SOXHyQewK4FinnJc5xIIMUNl6A9GWn6pQ0J1TmpiMSKVF1cStwv0UsaGor6S\/qDm9MkSY57hvEMjOyOTsvmzewdGjnOZLis1g7VqDfgl9ENRC96UIdL1Qq4J9iJ6XyaH3Ns7nhc3qvybh5JJC5vxz0b3O8xK9TY8vEldL+8WRBuyG3tSRGaf\/BBNqlWnTiafI6cVo8jgQCZqCipjQOgzRH9TaO\/wSRcUXr+ARRm2kIwEMkFZVC9nUfnSAOcPWo4AZ0dzcP+CQiwAaDW5YKExBEMYMGfhv1fXsej95BciqNHqeKq3zhFJb8rQDgJj5C3T\/Nbq\/kFqOPvBa+Wg\/InrjVSNdOyRNN4wWXG54aa1pkR+fF6qcCYFxghqJSjqK2bqHqKZkRIuGJDzO++gr9GJo6tjbFLpCtDzWJoZjaAnBjvTjWJIdbWN3h07GBN9UAe6H31wgePJV2\/yb6dZxyn8+LhroyMYP2yxWLw==`,
+	"permissionlist":       `permid=1 permname=b_serverinstance_help_view permdesc=Retrieve\sinformation\sabout\sServerQuery\scommands|permid=2 permname=b_serverinstance_version_view permdesc=Retrieve\sglobal\sserver\sversion\s(including\splatform\sand\sbuild\snumber)|permid=133 permname=i_client_talk_power permdesc=Talk\spower|permid=167 permname=b_channel_join_permanent permdesc=Join\spermanent\schannels`,
+	"permidgetbyname":      `permsid=i_client_talk_power permid=133|permsid=b_channel_join_permanent permid=167`,
+	"permget":              `permsid=i_client_talk_power permid=133 permvalue=75`,
+	"permfind":             `t=0 id1=6 id2=0 p=133|t=3 id1=5 id2=0 p=133`,
+	"permoverview":         `t=0 id1=6 id2=0 p=133 v=50 n=0 s=0|t=1 id1=19 id2=0 p=133 v=60 n=0 s=1|t=3 id1=5 id2=39 p=133 v=10 n=0 s=0`,
+	"channelgrouplist":     `cgid=5 name=Channel\sAdmin type=1 iconid=100 savedb=1 sortid=0 namemode=0 n_modifyp=75 n_member_addp=50 n_member_removep=50|cgid=8 name=Guest type=1 iconid=0 savedb=1 sortid=0 namemode=0 n_modifyp=75 n_member_addp=50 n_member_removep=50`,
 	cmdQuit:                "",
+
+	"servergroupsbyclientid": `name=Server\sAdmin sgid=6 cldbid=19|name=Normal sgid=8 cldbid=19`,
+	"servergroupclientlist":  `cldbid=19 client_nickname=bdeb1337 client_unique_identifier=DZhdQU58qyooEK4Fr8Ly738hEmc=|cldbid=7 client_nickname=MuhChy client_unique_identifier=P8FKaVzyXhtJtD5Uf8t5bNjDpiM=`,
+	"servergroupaddclient":   "",
+	"servergroupdelclient":   "",
+	"clientgetdbidfromuid":   `cluid=DZhdQU58qyooEK4Fr8Ly738hEmc= cldbid=19`,
+
+	"banlist":   `banid=5 ip=1.2.3.4 name uid lastnickname=Jeff created=1259147468 duration=3600 invokername=serveradmin invokercldbid=1 invokeruid=serveradmin reason=spam enforcements=2|banid=6 ip name uid=DZhdQU58qyooEK4Fr8Ly738hEmc= mytsid lastnickname=bdeb1337 created=1259147468 duration=0 invokername=serveradmin invokercldbid=1 invokeruid=serveradmin reason enforcements=0`,
+	"banadd":    `banid=7`,
+	"banclient": `banid=8|banid=9`,
+	"bandel":    "",
+	"bandelall": "",
+
+	"complainlist":   `tcldbid=19 tname=bdeb1337 fcldbid=7 fname=MuhChy message=Bad\sguy timestamp=1259527003|tcldbid=19 tname=bdeb1337 fcldbid=1 fname=serveradmin message=Spamming timestamp=1259527100`,
+	"complainadd":    "",
+	"complaindel":    "",
+	"complaindelall": "",
+
+	"clientkick": "",
+	"clientpoke": "",
+	"clientmove": "",
+
+	"sendtextmessage": "",
+	"gm":              "",
+
+	"messagelist":       `msgid=1 cluid=DZhdQU58qyooEK4Fr8Ly738hEmc= subject=Hello flag_read=0 timestamp=1691527133|msgid=2 cluid=P8FKaVzyXhtJtD5Uf8t5bNjDpiM= subject=Re:\sHello flag_read=1 timestamp=1691527200`,
+	"messageget":        `msgid=1 cluid=DZhdQU58qyooEK4Fr8Ly738hEmc= subject=Hello message=How\sare\syou? timestamp=1691527133`,
+	"messageadd":        "",
+	"messagedel":        "",
+	"messageupdateflag": "",
+	"clientlist -uid":   `clid=42087 cid=39 client_database_id=19 client_nickname=bdeb1337 client_type=0 client_unique_identifier=DZhdQU58qyooEK4Fr8Ly738hEmc=`,
+
+	"logview": `last_pos=403788 file_size=411980 l=2017-06-26\s21:55:30.307009\p\sINFO\s\s\s\s\pQuery\s\s\s\s\s\s\s\s\s\p\s\s\s\pquery\sfrom\s47\s[::1]:63629\sissued:\slogin\swith\saccount\s'serveradmin'(serveradmin)|l=2017-06-26\s21:55:31.000001\pWARNING\s\pVirtualServer\s\p1\s\s\pclient\sdisconnected`,
+	"logadd":  "",
+
+	"ftgetfilelist": `cid=5 path=\/ name=docs size=0 datetime=1691527133 type=0|cid=5 path=\/ name=readme.txt size=11 datetime=1691527200 type=1`,
+	"ftgetfileinfo": `cid=5 name=\/readme.txt size=11 datetime=1691527200|cid=5 name=\/docs\/a.txt size=5 datetime=1691527300`,
+	"ftcreatedir":   "",
+	"ftdeletefile":  "",
+	"ftrenamefile":  "",
+	"ftlist":        `clid=42087 path=files\/virtualserver_1\/channel_5 name=big.iso size=1048576 sizedone=524288 clientftfid=1 serverftfid=6 sender=0 status=1 current_speed=1024.5 average_speed=512.25 runtime=2500`,
+	"ftstop":        "",
+
+	"servergroupaddperm":  "",
+	"channelgroupaddperm": "",
+	"channeladdperm":      "",
+	"clientupdate":        "",
+	"clientlist -icon":    `clid=42087 cid=39 client_database_id=19 client_nickname=bdeb1337 client_type=0 client_icon_id=-1294967296`,
+
+	"privilegekeydelete": "",
+	"privilegekeyuse":    "",
+
+	"hostinfo":          "instance_uptime=1903 host_timestamp_utc=1499948005 virtualservers_running_total=1 virtualservers_total_maxclients=32 virtualservers_total_clients_online=2 virtualservers_total_channels_online=3 connection_filetransfer_bandwidth_sent=0 connection_filetransfer_bandwidth_received=0 connection_filetransfer_bytes_sent_total=617 connection_filetransfer_bytes_received_total=0 connection_packets_sent_total=926413 connection_bytes_sent_total=92911395 connection_packets_received_total=650335 connection_bytes_received_total=61940731 connection_bandwidth_sent_last_second_total=81 connection_bandwidth_sent_last_minute_total=92 connection_bandwidth_received_last_second_total=83 connection_bandwidth_received_last_minute_total=88",
+	"instanceedit":      "",
+	"bindinglist":       `ip=0.0.0.0|ip=::`,
+	"serverprocessstop": "",
+	"queryloginadd":     `cldbid=19 sid=1 client_login_name=orchestrator client_login_password=+r7ZwY3n`,
+	"querylogindel":     "",
+	"queryloginlist":    `cldbid=19 sid=1 client_login_name=orchestrator|cldbid=20 sid=0 client_login_name=serveradmin`,
+
+	"servertemppasswordadd":  "",
+	"servertemppassworddel":  "",
+	"servertemppasswordlist": `nickname=serveradmin uid=serveradmin desc=match\s42 pw_clear=s3cret start=1499948005 end=1499951605 tcid=39|nickname=bdeb1337 uid=fBdHMWGZ6Nkd3wB7RITGNWGkbOw= desc pw_clear=guest start=1499948005 end=1499948065 tcid=0`,
 }
 
 // newLockListener creates a new listener on the local IP.
@@ -73,6 +142,7 @@ type server struct {
 	failConn  bool
 	badHeader bool
 	useSSH    bool
+	handlers  map[string]func(line string) string
 
 	// Below here is protected by mtx.
 	mtx    sync.Mutex
@@ -118,6 +188,18 @@ func badHeader() serverOption {
 	}
 }
 
+// handler sets f as the handler for cmd, overriding the static response in commands.
+// f is passed the full command line and returns the response, if the response
+// starts with "error " it's sent as is instead of being followed by an ok error.
+func handler(cmd string, f func(line string) string) serverOption {
+	return func(s *server) {
+		if s.handlers == nil {
+			s.handlers = make(map[string]func(line string) string)
+		}
+		s.handlers[cmd] = f
+	}
+}
+
 // newServer returns a running server. It fails the test immediately if an error occurred.
 func newServer(t *testing.T, options ...serverOption) *server {
 	t.Helper()
@@ -264,8 +346,14 @@ func (s *server) handle(conn net.Conn) {
 			cmd = l
 		}
 		resp, ok := commands[cmd]
+		if h, found := s.handlers[cmd]; found {
+			resp, ok = h(l), true
+		}
 		var err error
 		switch {
+		case ok && strings.HasPrefix(resp, "error "):
+			// Request has an error response, send it.
+			err = s.write(c, resp)
 		case ok:
 			// Request has response, send it.
 			err = s.writeResponse(c, resp)
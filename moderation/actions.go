@@ -0,0 +1,104 @@
+package moderation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+// Action is performed on the client of an event which violates a rule.
+type Action interface {
+	// Apply performs the action on the client of ev.
+	Apply(s Server, ev *Event) error
+
+	// String returns a description of the action for the audit log.
+	String() string
+}
+
+// action is a simple Action implementation.
+type action struct {
+	desc  string
+	apply func(s Server, ev *Event) error
+}
+
+// Apply implements Action.
+func (a *action) Apply(s Server, ev *Event) error {
+	return a.apply(s, ev)
+}
+
+func (a *action) String() string {
+	return a.desc
+}
+
+// KickFromServer returns an Action which kicks the client from the server with msg.
+func KickFromServer(msg string) Action {
+	return &action{
+		desc: "kick from server",
+		apply: func(s Server, ev *Event) error {
+			return s.ClientKick(ts3.KickFromServer, msg, ev.ClientID)
+		},
+	}
+}
+
+// KickFromChannel returns an Action which kicks the client from its channel with msg.
+func KickFromChannel(msg string) Action {
+	return &action{
+		desc: "kick from channel",
+		apply: func(s Server, ev *Event) error {
+			return s.ClientKick(ts3.KickFromChannel, msg, ev.ClientID)
+		},
+	}
+}
+
+// Ban returns an Action which bans the client for duration with reason.
+// A zero duration is a permanent ban.
+func Ban(duration time.Duration, reason string) Action {
+	return &action{
+		desc: fmt.Sprintf("ban for %v", duration),
+		apply: func(s Server, ev *Event) error {
+			_, err := s.BanClient(ev.ClientID, duration, reason)
+			return err
+		},
+	}
+}
+
+// Poke returns an Action which pokes the client with msg.
+func Poke(msg string) Action {
+	return &action{
+		desc: "poke",
+		apply: func(s Server, ev *Event) error {
+			return s.ClientPoke(ev.ClientID, msg)
+		},
+	}
+}
+
+// Move returns an Action which moves the client to the channel cid.
+func Move(cid int) Action {
+	return &action{
+		desc: fmt.Sprintf("move to channel %d", cid),
+		apply: func(s Server, ev *Event) error {
+			return s.ClientMove(cid, "", ev.ClientID)
+		},
+	}
+}
+
+// AddServerGroup returns an Action which adds the client to the server group sgid.
+func AddServerGroup(sgid int) Action {
+	return &action{
+		desc: fmt.Sprintf("add to server group %d", sgid),
+		apply: func(s Server, ev *Event) error {
+			return s.ServerGroupAddClient(sgid, ev.DatabaseID)
+		},
+	}
+}
+
+// RemoveServerGroup returns an Action which removes the client from the server group sgid.
+func RemoveServerGroup(sgid int) Action {
+	return &action{
+		desc: fmt.Sprintf("remove from server group %d", sgid),
+		apply: func(s Server, ev *Event) error {
+			return s.ServerGroupDelClient(sgid, ev.DatabaseID)
+		},
+	}
+}
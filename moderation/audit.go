@@ -0,0 +1,66 @@
+package moderation
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry records an action taken, or which would have been taken in dry-run mode.
+type AuditEntry struct {
+	Time             time.Time
+	Rule             string
+	Reason           string
+	Action           string
+	ClientID         int
+	UniqueIdentifier string
+	Nickname         string
+	DryRun           bool
+	Err              error
+}
+
+func (e *AuditEntry) String() string {
+	s := fmt.Sprintf("%v rule=%q action=%q clid=%d uid=%q nickname=%q reason=%q",
+		e.Time.Format(time.RFC3339), e.Rule, e.Action, e.ClientID, e.UniqueIdentifier, e.Nickname, e.Reason,
+	)
+	if e.DryRun {
+		s += " dry-run"
+	}
+	if e.Err != nil {
+		s += fmt.Sprintf(" error=%q", e.Err)
+	}
+	return s
+}
+
+// AuditLog records the actions taken by a Moderator.
+type AuditLog interface {
+	Record(e *AuditEntry)
+}
+
+// AuditLogFunc is an adapter to allow the use of ordinary functions as an AuditLog.
+type AuditLogFunc func(e *AuditEntry)
+
+// Record implements AuditLog.
+func (f AuditLogFunc) Record(e *AuditEntry) {
+	f(e)
+}
+
+// writerAuditLog is an AuditLog which writes entries to an io.Writer.
+type writerAuditLog struct {
+	mtx sync.Mutex
+	w   io.Writer
+}
+
+// NewWriterAuditLog returns an AuditLog which writes each entry as a line to w.
+func NewWriterAuditLog(w io.Writer) AuditLog {
+	return &writerAuditLog{w: w}
+}
+
+// Record implements AuditLog.
+func (l *writerAuditLog) Record(e *AuditEntry) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	fmt.Fprintln(l.w, e.String()) //nolint: errcheck
+}
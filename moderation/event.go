@@ -0,0 +1,86 @@
+package moderation
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// MessageEvent is a text message sent by a client.
+	MessageEvent EventType = "textmessage"
+
+	// EnterEvent is a client connecting to the server.
+	EnterEvent EventType = "cliententerview"
+
+	// MoveEvent is a client moving, or being moved, to a different channel.
+	MoveEvent EventType = "clientmoved"
+)
+
+// Event is a moderation relevant event derived from a notification.
+// Client details which aren't part of the notification are filled in
+// from the clients known to the Moderator.
+type Event struct {
+	Type             EventType
+	Time             time.Time
+	ClientID         int
+	DatabaseID       int
+	UniqueIdentifier string
+	Nickname         string
+	Country          string
+	ChannelID        int // ChannelID is the channel the client entered or moved to.
+
+	// Only set for MessageEvent.
+	Message    string
+	TargetMode ts3.TargetMode
+}
+
+// client is the state of an online client.
+type client struct {
+	databaseID       int
+	uniqueIdentifier string
+	nickname         string
+	country          string
+	channelID        int
+}
+
+// newEvent returns the Event for n or false if n isn't relevant.
+func newEvent(n ts3.Notification, now time.Time) (*Event, bool) {
+	ev := &Event{Type: EventType(n.Type), Time: now}
+	switch ev.Type {
+	case MessageEvent:
+		ev.ClientID = atoi(n.Data["invokerid"])
+		ev.UniqueIdentifier = n.Data["invokeruid"]
+		ev.Nickname = n.Data["invokername"]
+		ev.Message = n.Data["msg"]
+		ev.TargetMode = ts3.TargetMode(atoi(n.Data["targetmode"]))
+	case EnterEvent:
+		if n.Data["client_type"] == "1" {
+			// Ignore query clients.
+			return nil, false
+		}
+		ev.ClientID = atoi(n.Data["clid"])
+		ev.DatabaseID = atoi(n.Data["client_database_id"])
+		ev.UniqueIdentifier = n.Data["client_unique_identifier"]
+		ev.Nickname = n.Data["client_nickname"]
+		ev.Country = n.Data["client_country"]
+		ev.ChannelID = atoi(n.Data["ctid"])
+	case MoveEvent:
+		ev.ClientID = atoi(n.Data["clid"])
+		ev.ChannelID = atoi(n.Data["ctid"])
+	default:
+		return nil, false
+	}
+
+	return ev, ev.ClientID != 0
+}
+
+// atoi returns s as an int or 0 if it's not valid.
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
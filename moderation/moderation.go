@@ -0,0 +1,302 @@
+// Package moderation provides rule based automatic moderation for TeamSpeak 3 servers.
+//
+// A Moderator consumes textmessage, cliententerview and clientmoved
+// notifications, evaluates them against a set of rules and performs the
+// actions of any rule which matches, such as kicking, banning or moving the
+// client. Every action is recorded in an audit log.
+//
+// The client must be registered for the relevant events, for example:
+//
+//	c.Register(ts3.ServerEvents)
+//	c.Register(ts3.ChannelEvents)
+//	c.Register(ts3.TextServerEvents)
+//	c.Register(ts3.TextPrivateEvents)
+//	m.Run(ctx, c.Notifications())
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+var (
+	// ErrInvalidRule is returned by New if a rule has no Matcher or Actions.
+	ErrInvalidRule = errors.New("invalid rule")
+)
+
+// Server is the subset of ts3.ServerMethods used by a Moderator.
+type Server interface {
+	ClientList(options ...string) ([]*ts3.OnlineClient, error)
+	ClientKick(reason ts3.KickReason, msg string, clids ...int) error
+	ClientPoke(clid int, msg string) error
+	ClientMove(cid int, cpw string, clids ...int) error
+	BanClient(clid int, duration time.Duration, reason string) ([]int, error)
+	ServerGroupAddClient(sgid int, cldbids ...int) error
+	ServerGroupDelClient(sgid int, cldbids ...int) error
+}
+
+// Rule is a moderation rule.
+type Rule struct {
+	// Name identifies the rule in the audit log.
+	Name string
+
+	// Matcher decides whether an event violates the rule.
+	Matcher Matcher
+
+	// Actions are performed, in order, on the client of a violating event.
+	Actions []Action
+
+	// Cooldown is the minimum time between applying the rule to the same client.
+	Cooldown time.Duration
+}
+
+// Moderator evaluates events against rules and acts on violations.
+type Moderator struct {
+	server Server
+	rules  []*Rule
+	audit  AuditLog
+	dryRun bool
+	ignore map[string]bool
+	now    func() time.Time
+
+	mtx     sync.Mutex
+	clients map[int]*client
+	fired   map[string]time.Time // fired is when a rule can next be applied to a client.
+}
+
+// DryRun configures the Moderator to only record the actions it would take.
+func DryRun() func(*Moderator) error {
+	return func(m *Moderator) error {
+		m.dryRun = true
+		return nil
+	}
+}
+
+// Audit sets the audit log used to record actions.
+func Audit(l AuditLog) func(*Moderator) error {
+	return func(m *Moderator) error {
+		m.audit = l
+		return nil
+	}
+}
+
+// Ignore sets the unique identifiers of clients which are never moderated.
+func Ignore(uids ...string) func(*Moderator) error {
+	return func(m *Moderator) error {
+		for _, uid := range uids {
+			m.ignore[uid] = true
+		}
+		return nil
+	}
+}
+
+// New returns a new Moderator which enforces rules using server.
+func New(server Server, rules []*Rule, options ...func(*Moderator) error) (*Moderator, error) {
+	for _, r := range rules {
+		if r.Matcher == nil || len(r.Actions) == 0 {
+			return nil, fmt.Errorf("moderation: rule %q: %w", r.Name, ErrInvalidRule)
+		}
+	}
+
+	m := &Moderator{
+		server:  server,
+		rules:   rules,
+		audit:   AuditLogFunc(func(*AuditEntry) {}),
+		ignore:  make(map[string]bool),
+		now:     time.Now,
+		clients: make(map[int]*client),
+		fired:   make(map[string]time.Time),
+	}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Run handles notifications from ch until ctx is done or ch is closed.
+func (m *Moderator) Run(ctx context.Context, ch <-chan ts3.Notification) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("moderation: run: %w", ctx.Err())
+		case n, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			m.Handle(n)
+		}
+	}
+}
+
+// Handle evaluates the notification n against the rules and performs the
+// actions of any matching rules. Irrelevant notifications are ignored.
+func (m *Moderator) Handle(n ts3.Notification) {
+	if n.Type == "clientleftview" {
+		m.leave(atoi(n.Data["clid"]))
+		return
+	}
+
+	ev, ok := newEvent(n, m.now())
+	if !ok {
+		return
+	}
+
+	m.track(ev)
+	if m.ignore[ev.UniqueIdentifier] {
+		return
+	}
+
+	for _, r := range m.rules {
+		reason, ok := r.Matcher.Match(ev)
+		if !ok || !m.ready(r, ev) {
+			continue
+		}
+
+		for _, a := range r.Actions {
+			e := &AuditEntry{
+				Time:             ev.Time,
+				Rule:             r.Name,
+				Reason:           reason,
+				Action:           a.String(),
+				ClientID:         ev.ClientID,
+				UniqueIdentifier: ev.UniqueIdentifier,
+				Nickname:         ev.Nickname,
+				DryRun:           m.dryRun,
+			}
+			if !m.dryRun {
+				e.Err = a.Apply(m.server, ev)
+			}
+			m.audit.Record(e)
+		}
+	}
+}
+
+// ready returns true if rule r can be applied to the client of ev,
+// recording that it has been if so.
+func (m *Moderator) ready(r *Rule, ev *Event) bool {
+	if r.Cooldown <= 0 {
+		return true
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	key := r.Name + "\x00" + clientKey(ev)
+	if next, ok := m.fired[key]; ok && ev.Time.Before(next) {
+		return false
+	}
+	m.fired[key] = ev.Time.Add(r.Cooldown)
+
+	return true
+}
+
+// leave forgets the client clid which left the server and removes
+// cooldowns which have expired.
+func (m *Moderator) leave(clid int) {
+	now := m.now()
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.clients, clid)
+	for key, next := range m.fired {
+		if !now.Before(next) {
+			delete(m.fired, key)
+		}
+	}
+}
+
+// track updates the known client state from ev and fills in any missing client details.
+func (m *Moderator) track(ev *Event) {
+	m.mtx.Lock()
+	c, ok := m.clients[ev.ClientID]
+	m.mtx.Unlock()
+
+	if ev.Type == EnterEvent || !ok {
+		if ev.Type != EnterEvent {
+			// Client we haven't seen enter, look it up without holding
+			// the lock so other events aren't blocked by the request.
+			c = m.lookup(ev.ClientID)
+		} else {
+			c = &client{
+				databaseID:       ev.DatabaseID,
+				uniqueIdentifier: ev.UniqueIdentifier,
+				nickname:         ev.Nickname,
+				country:          ev.Country,
+			}
+		}
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if ev.Type != EnterEvent {
+		if known, ok := m.clients[ev.ClientID]; ok {
+			// Tracked by another event while the lookup was in progress.
+			c = known
+		}
+	}
+	m.clients[ev.ClientID] = c
+
+	if ev.Type != MessageEvent {
+		c.channelID = ev.ChannelID
+	}
+
+	if ev.DatabaseID == 0 {
+		ev.DatabaseID = c.databaseID
+	}
+	if ev.UniqueIdentifier == "" {
+		ev.UniqueIdentifier = c.uniqueIdentifier
+	}
+	if ev.Nickname == "" {
+		ev.Nickname = c.nickname
+	}
+	if ev.Country == "" {
+		ev.Country = c.country
+	}
+	if ev.ChannelID == 0 {
+		ev.ChannelID = c.channelID
+	}
+}
+
+// lookup returns the details of the online client clid from the server.
+// If the lookup fails an empty client is returned.
+func (m *Moderator) lookup(clid int) *client {
+	c := &client{}
+	clients, err := m.server.ClientList(ts3.ClientUID, ts3.ClientCountry)
+	if err != nil {
+		return c
+	}
+
+	for _, oc := range clients {
+		if oc.ID != clid {
+			continue
+		}
+
+		c.databaseID = oc.DatabaseID
+		c.nickname = oc.Nickname
+		c.channelID = oc.ChannelID
+		if oc.OnlineClientExt != nil {
+			if oc.UniqueIdentifier != nil {
+				c.uniqueIdentifier = *oc.UniqueIdentifier
+			}
+			if oc.Country != nil {
+				c.country = *oc.Country
+			}
+		}
+		break
+	}
+
+	return c
+}
@@ -0,0 +1,244 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer records the calls made by a Moderator.
+type fakeServer struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeServer) record(format string, args ...interface{}) error {
+	f.calls = append(f.calls, fmt.Sprintf(format, args...))
+	return f.err
+}
+
+func (f *fakeServer) ClientList(options ...string) ([]*ts3.OnlineClient, error) {
+	uid := "looked-up-uid"
+	country := "XX"
+	return []*ts3.OnlineClient{
+		{
+			ID:              7,
+			ChannelID:       1,
+			DatabaseID:      70,
+			Nickname:        "lurker",
+			OnlineClientExt: &ts3.OnlineClientExt{UniqueIdentifier: &uid, Country: &country},
+		},
+	}, nil
+}
+
+func (f *fakeServer) ClientKick(reason ts3.KickReason, msg string, clids ...int) error {
+	return f.record("kick %v %q %v", reason, msg, clids)
+}
+
+func (f *fakeServer) ClientPoke(clid int, msg string) error {
+	return f.record("poke %v %q", clid, msg)
+}
+
+func (f *fakeServer) ClientMove(cid int, cpw string, clids ...int) error {
+	return f.record("move %v %v", cid, clids)
+}
+
+func (f *fakeServer) BanClient(clid int, duration time.Duration, reason string) ([]int, error) {
+	return nil, f.record("ban %v %v %q", clid, duration, reason)
+}
+
+func (f *fakeServer) ServerGroupAddClient(sgid int, cldbids ...int) error {
+	return f.record("sgadd %v %v", sgid, cldbids)
+}
+
+func (f *fakeServer) ServerGroupDelClient(sgid int, cldbids ...int) error {
+	return f.record("sgdel %v %v", sgid, cldbids)
+}
+
+func enter(clid int, uid, nick, country string) ts3.Notification {
+	return ts3.Notification{Type: "cliententerview", Data: map[string]string{
+		"ctid":                     "1",
+		"clid":                     fmt.Sprint(clid),
+		"client_database_id":       fmt.Sprint(clid * 10),
+		"client_unique_identifier": uid,
+		"client_nickname":          nick,
+		"client_country":           country,
+		"client_type":              "0",
+	}}
+}
+
+func message(clid int, uid, msg string) ts3.Notification {
+	return ts3.Notification{Type: "textmessage", Data: map[string]string{
+		"targetmode":  "3",
+		"msg":         msg,
+		"invokerid":   fmt.Sprint(clid),
+		"invokername": "someone",
+		"invokeruid":  uid,
+	}}
+}
+
+func moved(clid, cid int) ts3.Notification {
+	return ts3.Notification{Type: "clientmoved", Data: map[string]string{
+		"ctid":     fmt.Sprint(cid),
+		"reasonid": "0",
+		"clid":     fmt.Sprint(clid),
+	}}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&fakeServer{}, []*Rule{{Name: "empty"}})
+	assert.True(t, errors.Is(err, ErrInvalidRule))
+
+	_, err = New(&fakeServer{}, nil, nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+}
+
+func TestModerator(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rules := []*Rule{
+		{
+			Name:    "nickname",
+			Matcher: Nickname(regexp.MustCompile(`(?i)admin`)),
+			Actions: []Action{KickFromServer("bad nickname")},
+		},
+		{
+			Name:     "words",
+			Matcher:  BannedWords("spam"),
+			Actions:  []Action{Poke("no spam please"), AddServerGroup(9)},
+			Cooldown: time.Minute,
+		},
+		{
+			Name:    "country",
+			Matcher: Country("XX"),
+			Actions: []Action{Move(5), RemoveServerGroup(8)},
+		},
+		{
+			Name:    "flood",
+			Matcher: MessageRate(1, time.Second),
+			Actions: []Action{Ban(time.Hour, "flood"), KickFromChannel("flood")},
+		},
+	}
+
+	var audit []*AuditEntry
+	srv := &fakeServer{}
+	m, err := New(srv, rules,
+		Audit(AuditLogFunc(func(e *AuditEntry) { audit = append(audit, e) })),
+		Ignore("trusted"),
+	)
+	require.NoError(t, err)
+	m.now = func() time.Time { return now }
+
+	m.Handle(enter(1, "uid1", "Admin", "BE"))
+	m.Handle(enter(2, "trusted", "admin", "XX"))
+	m.Handle(enter(3, "uid3", "bob", "BE"))
+	m.Handle(ts3.Notification{Type: "cliententerview", Data: map[string]string{"clid": "4", "client_type": "1"}})
+	m.Handle(message(3, "uid3", "spam"))
+	now = now.Add(time.Second * 10)
+	m.Handle(message(3, "uid3", "more spam")) // Cooldown.
+	m.Handle(moved(7, 2))                     // Unknown client, looked up.
+	now = now.Add(time.Second * 10)
+	m.Handle(message(3, "uid3", "hi"))
+	m.Handle(message(3, "uid3", "hi again"))
+	m.Handle(ts3.Notification{Type: "clientleftview", Data: map[string]string{"clid": "3"}})
+	m.Handle(ts3.Notification{Type: "serveredited"})
+
+	expected := []string{
+		`kick 5 "bad nickname" [1]`,
+		`poke 3 "no spam please"`,
+		`sgadd 9 [30]`,
+		`move 5 [7]`,
+		`sgdel 8 [70]`,
+		`ban 3 1h0m0s "flood"`,
+		`kick 4 "flood" [3]`,
+	}
+	assert.Equal(t, expected, srv.calls)
+
+	require.Len(t, audit, len(expected))
+	assert.Equal(t, "nickname", audit[0].Rule)
+	assert.Equal(t, "Admin", audit[0].Nickname)
+	assert.Equal(t, "country", audit[3].Rule)
+	assert.Equal(t, "looked-up-uid", audit[3].UniqueIdentifier)
+	assert.Equal(t, "lurker", audit[3].Nickname)
+	for _, e := range audit {
+		assert.False(t, e.DryRun)
+		assert.NoError(t, e.Err)
+	}
+	assert.NotContains(t, m.clients, 3)
+}
+
+func TestModeratorPrune(t *testing.T) {
+	now := time.Unix(1000, 0)
+	srv := &fakeServer{}
+	rules := []*Rule{{Name: "words", Matcher: BannedWords("spam"), Actions: []Action{Poke("")}, Cooldown: time.Minute}}
+	m, err := New(srv, rules)
+	require.NoError(t, err)
+	m.now = func() time.Time { return now }
+
+	m.Handle(enter(3, "uid3", "bob", "BE"))
+	m.Handle(message(3, "uid3", "spam"))
+	m.Handle(enter(5, "uid5", "alice", "BE"))
+	m.Handle(message(5, "uid5", "spam"))
+	assert.Len(t, m.fired, 2)
+
+	// Cooldowns which haven't expired are kept.
+	m.Handle(ts3.Notification{Type: "clientleftview", Data: map[string]string{"clid": "3"}})
+	assert.Len(t, m.clients, 1)
+	assert.Len(t, m.fired, 2)
+
+	now = now.Add(time.Minute)
+	m.Handle(ts3.Notification{Type: "clientleftview", Data: map[string]string{"clid": "5"}})
+	assert.Empty(t, m.clients)
+	assert.Empty(t, m.fired)
+}
+
+func TestModeratorDryRun(t *testing.T) {
+	var buf bytes.Buffer
+	srv := &fakeServer{}
+	rules := []*Rule{{Name: "words", Matcher: BannedWords("spam"), Actions: []Action{KickFromServer("")}}}
+	m, err := New(srv, rules, DryRun(), Audit(NewWriterAuditLog(&buf)))
+	require.NoError(t, err)
+
+	m.Handle(message(3, "uid3", "spam"))
+	assert.Empty(t, srv.calls)
+	assert.Contains(t, buf.String(), `rule="words" action="kick from server" clid=3 uid="uid3"`)
+	assert.Contains(t, buf.String(), "dry-run")
+}
+
+func TestModeratorActionError(t *testing.T) {
+	var audit []*AuditEntry
+	srv := &fakeServer{err: errors.New("insufficient client permissions")}
+	rules := []*Rule{{Name: "words", Matcher: BannedWords("spam"), Actions: []Action{Poke("")}}}
+	m, err := New(srv, rules, Audit(AuditLogFunc(func(e *AuditEntry) { audit = append(audit, e) })))
+	require.NoError(t, err)
+
+	m.Handle(message(3, "uid3", "spam"))
+	require.Len(t, audit, 1)
+	assert.Equal(t, srv.err, audit[0].Err)
+	assert.Contains(t, audit[0].String(), "insufficient client permissions")
+}
+
+func TestModeratorRun(t *testing.T) {
+	srv := &fakeServer{}
+	rules := []*Rule{{Name: "words", Matcher: BannedWords("spam"), Actions: []Action{Poke("")}}}
+	m, err := New(srv, rules)
+	require.NoError(t, err)
+
+	ch := make(chan ts3.Notification, 1)
+	ch <- message(3, "uid3", "spam")
+	close(ch)
+	assert.NoError(t, m.Run(context.Background(), ch))
+	assert.Len(t, srv.calls, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = m.Run(ctx, make(chan ts3.Notification))
+	assert.True(t, errors.Is(err, context.Canceled))
+}
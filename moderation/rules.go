@@ -0,0 +1,161 @@
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Matcher decides whether an event violates a rule.
+type Matcher interface {
+	// Match returns true and a description of the violation if ev violates the rule.
+	Match(ev *Event) (string, bool)
+}
+
+// MatcherFunc is an adapter to allow the use of ordinary functions as a Matcher.
+type MatcherFunc func(ev *Event) (string, bool)
+
+// Match implements Matcher.
+func (f MatcherFunc) Match(ev *Event) (string, bool) {
+	return f(ev)
+}
+
+// Nickname returns a Matcher which matches clients whose nickname matches re
+// when they enter the server or send a message.
+func Nickname(re *regexp.Regexp) Matcher {
+	return MatcherFunc(func(ev *Event) (string, bool) {
+		if ev.Type == MoveEvent || !re.MatchString(ev.Nickname) {
+			return "", false
+		}
+		return fmt.Sprintf("nickname %q matches %q", ev.Nickname, re), true
+	})
+}
+
+// BannedWords returns a Matcher which matches messages containing any of words.
+// Words are matched case insensitively on word boundaries, empty words are ignored.
+// If there are no words the Matcher never matches.
+func BannedWords(words ...string) Matcher {
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		if w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return MatcherFunc(func(*Event) (string, bool) {
+			return "", false
+		})
+	}
+	re := regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+
+	return MatcherFunc(func(ev *Event) (string, bool) {
+		if ev.Type != MessageEvent {
+			return "", false
+		}
+		if w := re.FindString(ev.Message); w != "" {
+			return fmt.Sprintf("message contains banned word %q", w), true
+		}
+		return "", false
+	})
+}
+
+// Country returns a Matcher which matches clients from one of the countries,
+// specified as ISO 3166-1 alpha-2 codes, when they enter the server or move.
+func Country(codes ...string) Matcher {
+	set := countrySet(codes)
+	return MatcherFunc(func(ev *Event) (string, bool) {
+		if ev.Type == MessageEvent || ev.Country == "" || !set[strings.ToUpper(ev.Country)] {
+			return "", false
+		}
+		return fmt.Sprintf("country %v is not allowed", ev.Country), true
+	})
+}
+
+// CountryNotIn returns a Matcher which matches clients which aren't from one of the
+// countries, specified as ISO 3166-1 alpha-2 codes, when they enter the server or move.
+// Clients without a known country never match.
+func CountryNotIn(codes ...string) Matcher {
+	set := countrySet(codes)
+	return MatcherFunc(func(ev *Event) (string, bool) {
+		if ev.Type == MessageEvent || ev.Country == "" || set[strings.ToUpper(ev.Country)] {
+			return "", false
+		}
+		return fmt.Sprintf("country %v is not allowed", ev.Country), true
+	})
+}
+
+func countrySet(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		set[strings.ToUpper(c)] = true
+	}
+	return set
+}
+
+// messageRate is a Matcher which detects message spam.
+type messageRate struct {
+	max    int
+	window time.Duration
+
+	mtx   sync.Mutex
+	sent  map[string][]time.Time
+	swept time.Time // swept is when sent was last cleared of idle clients.
+}
+
+// MessageRate returns a Matcher which matches clients which send more than max messages within window.
+func MessageRate(max int, window time.Duration) Matcher {
+	return &messageRate{
+		max:    max,
+		window: window,
+		sent:   make(map[string][]time.Time),
+	}
+}
+
+// Match implements Matcher.
+func (m *messageRate) Match(ev *Event) (string, bool) {
+	if ev.Type != MessageEvent {
+		return "", false
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	key := clientKey(ev)
+	cutoff := ev.Time.Add(-m.window)
+	sent := append(m.sent[key], ev.Time)
+	for len(sent) > 0 && !sent[0].After(cutoff) {
+		sent = sent[1:]
+	}
+	m.sent[key] = sent
+	m.sweep(ev.Time, cutoff)
+
+	if len(sent) > m.max {
+		return fmt.Sprintf("sent %d messages in %v", len(sent), m.window), true
+	}
+	return "", false
+}
+
+// sweep removes the clients which haven't sent a message since cutoff,
+// at most once per window, so clients which left don't accumulate.
+func (m *messageRate) sweep(now, cutoff time.Time) {
+	if now.Sub(m.swept) < m.window {
+		return
+	}
+	m.swept = now
+
+	for key, sent := range m.sent {
+		if len(sent) == 0 || !sent[len(sent)-1].After(cutoff) {
+			delete(m.sent, key)
+		}
+	}
+}
+
+// clientKey returns the key identifying the client of ev.
+func clientKey(ev *Event) string {
+	if ev.UniqueIdentifier != "" {
+		return ev.UniqueIdentifier
+	}
+	return fmt.Sprintf("clid:%d", ev.ClientID)
+}
@@ -0,0 +1,95 @@
+package moderation
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNickname(t *testing.T) {
+	m := Nickname(regexp.MustCompile(`(?i)admin`))
+
+	reason, ok := m.Match(&Event{Type: EnterEvent, Nickname: "Not An Admin"})
+	assert.True(t, ok)
+	assert.NotEmpty(t, reason)
+
+	_, ok = m.Match(&Event{Type: EnterEvent, Nickname: "bob"})
+	assert.False(t, ok)
+
+	_, ok = m.Match(&Event{Type: MoveEvent, Nickname: "admin"})
+	assert.False(t, ok)
+}
+
+func TestBannedWords(t *testing.T) {
+	m := BannedWords("spam", "", "s.p.a.m")
+
+	tests := map[string]bool{
+		"buy SPAM now": true,
+		"spammer":      false,
+		"use s.p.a.m":  true,
+		"use sXpXaXm":  false,
+		"hello":        false,
+	}
+	for msg, expected := range tests {
+		_, ok := m.Match(&Event{Type: MessageEvent, Message: msg})
+		assert.Equal(t, expected, ok, msg)
+	}
+
+	_, ok := m.Match(&Event{Type: EnterEvent, Nickname: "spam"})
+	assert.False(t, ok)
+
+	for _, m := range []Matcher{BannedWords(), BannedWords("")} {
+		_, ok := m.Match(&Event{Type: MessageEvent, Message: "spam"})
+		assert.False(t, ok)
+	}
+}
+
+func TestCountry(t *testing.T) {
+	deny := Country("xx", "YY")
+	allow := CountryNotIn("BE")
+
+	tests := []struct {
+		ev    *Event
+		deny  bool
+		allow bool
+	}{
+		{&Event{Type: EnterEvent, Country: "XX"}, true, true},
+		{&Event{Type: MoveEvent, Country: "yy"}, true, true},
+		{&Event{Type: EnterEvent, Country: "BE"}, false, false},
+		{&Event{Type: EnterEvent}, false, false},
+		{&Event{Type: MessageEvent, Country: "XX"}, false, false},
+	}
+	for _, tc := range tests {
+		_, ok := deny.Match(tc.ev)
+		assert.Equal(t, tc.deny, ok, "deny %v", tc.ev.Country)
+		_, ok = allow.Match(tc.ev)
+		assert.Equal(t, tc.allow, ok, "allow %v", tc.ev.Country)
+	}
+}
+
+func TestMessageRate(t *testing.T) {
+	m := MessageRate(2, time.Second)
+	start := time.Unix(1000, 0)
+	msg := func(uid string, offset time.Duration) bool {
+		_, ok := m.Match(&Event{Type: MessageEvent, UniqueIdentifier: uid, Time: start.Add(offset)})
+		return ok
+	}
+
+	assert.False(t, msg("a", 0))
+	assert.False(t, msg("a", time.Millisecond*100))
+	assert.False(t, msg("b", time.Millisecond*150))
+	assert.True(t, msg("a", time.Millisecond*200))
+
+	// The first message is now outside of the window.
+	assert.False(t, msg("a", time.Millisecond*1300))
+
+	// Idle clients are removed.
+	assert.False(t, msg("c", time.Second*5))
+	assert.Len(t, m.(*messageRate).sent, 1)
+	assert.Contains(t, m.(*messageRate).sent, "c")
+
+	_, ok := m.Match(&Event{Type: EnterEvent, UniqueIdentifier: "a", Time: start})
+	assert.False(t, ok)
+}
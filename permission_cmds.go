@@ -0,0 +1,285 @@
+package ts3
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Permission represents a TeamSpeak 3 permission.
+type Permission struct {
+	ID          int    `ms:"permid"`
+	Name        string `ms:"permname"`
+	Description string `ms:"permdesc"`
+}
+
+// PermissionCatalog is an index of the permissions known by an instance
+// which allows resolving between permission IDs and names.
+type PermissionCatalog struct {
+	byID   map[int]*Permission
+	byName map[string]*Permission
+}
+
+// NewPermissionCatalog returns a new PermissionCatalog for perms.
+func NewPermissionCatalog(perms []*Permission) *PermissionCatalog {
+	pc := &PermissionCatalog{
+		byID:   make(map[int]*Permission, len(perms)),
+		byName: make(map[string]*Permission, len(perms)),
+	}
+	for _, p := range perms {
+		pc.byID[p.ID] = p
+		pc.byName[p.Name] = p
+	}
+	return pc
+}
+
+// ByID returns the permission with the given id.
+func (pc *PermissionCatalog) ByID(id int) (*Permission, bool) {
+	p, ok := pc.byID[id]
+	return p, ok
+}
+
+// ByName returns the permission with the given name.
+func (pc *PermissionCatalog) ByName(name string) (*Permission, bool) {
+	p, ok := pc.byName[name]
+	return p, ok
+}
+
+// Name returns the name of the permission id or an empty string if it's unknown.
+func (pc *PermissionCatalog) Name(id int) string {
+	if p, ok := pc.byID[id]; ok {
+		return p.Name
+	}
+	return ""
+}
+
+// All returns all permissions in the catalog ordered by ID.
+func (pc *PermissionCatalog) All() []*Permission {
+	perms := make([]*Permission, 0, len(pc.byID))
+	for _, p := range pc.byID {
+		perms = append(perms, p)
+	}
+	sort.Slice(perms, func(i, j int) bool {
+		return perms[i].ID < perms[j].ID
+	})
+	return perms
+}
+
+// PermissionList returns a list of all permissions available on the server instance.
+func (s *ServerMethods) PermissionList() ([]*Permission, error) {
+	var perms []*Permission
+	if _, err := s.ExecCmd(NewCmd("permissionlist").WithResponse(&perms)); err != nil {
+		return nil, err
+	}
+
+	// Newer servers include group markers in the list, skip them.
+	filtered := perms[:0]
+	for _, p := range perms {
+		if p.Name != "" {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered, nil
+}
+
+// Permissions returns the permission catalog for the connection.
+// The catalog is loaded using PermissionList on first use and then cached.
+func (s *ServerMethods) Permissions() (*PermissionCatalog, error) {
+	s.permsMtx.Lock()
+	defer s.permsMtx.Unlock()
+
+	if s.perms != nil {
+		return s.perms, nil
+	}
+
+	perms, err := s.PermissionList()
+	if err != nil {
+		return nil, err
+	}
+	s.perms = NewPermissionCatalog(perms)
+
+	return s.perms, nil
+}
+
+// PermissionArgs returns an ArgGroup of permid arguments for the given
+// permission names, resolved using the permission catalog.
+func (s *ServerMethods) PermissionArgs(names ...string) (*ArgGroup, error) {
+	pc, err := s.Permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]CmdArg, len(names))
+	for i, name := range names {
+		p, ok := pc.ByName(name)
+		if !ok {
+			return nil, fmt.Errorf("permission: unknown permission %q", name)
+		}
+		args[i] = NewArg("permid", p.ID)
+	}
+
+	return NewArgGroup(args...), nil
+}
+
+// PermIDGetByName returns the permissions, with ID and Name, for the given permission names.
+func (s *ServerMethods) PermIDGetByName(names ...string) ([]*Permission, error) {
+	args := make([]CmdArg, len(names))
+	for i, name := range names {
+		args[i] = NewArg("permsid", name)
+	}
+
+	var r []*struct {
+		ID   int    `ms:"permid"`
+		Name string `ms:"permsid"`
+	}
+	if _, err := s.ExecCmd(NewCmd("permidgetbyname").WithArgs(NewArgGroup(args...)).WithResponse(&r)); err != nil {
+		return nil, err
+	}
+
+	perms := make([]*Permission, len(r))
+	for i, p := range r {
+		perms[i] = &Permission{ID: p.ID, Name: p.Name}
+	}
+
+	return perms, nil
+}
+
+// PermissionValue represents the value of a permission.
+type PermissionValue struct {
+	ID      int    `ms:"permid"`
+	Name    string `ms:"permsid"`
+	Value   int    `ms:"permvalue"`
+	Negated bool   `ms:"permnegated"`
+	Skip    bool   `ms:"permskip"`
+}
+
+// PermGet returns the values of the given permissions for the current connection.
+func (s *ServerMethods) PermGet(names ...string) ([]*PermissionValue, error) {
+	args := make([]CmdArg, len(names))
+	for i, name := range names {
+		args[i] = NewArg("permsid", name)
+	}
+
+	var perms []*PermissionValue
+	if _, err := s.ExecCmd(NewCmd("permget").WithArgs(NewArgGroup(args...)).WithResponse(&perms)); err != nil {
+		return nil, err
+	}
+
+	return perms, nil
+}
+
+// PermissionType is the type of entity a permission is assigned to.
+type PermissionType int
+
+const (
+	// ServerGroupPermission is a permission assigned to a server group, ID1 is the server group ID.
+	ServerGroupPermission PermissionType = iota
+
+	// ClientPermission is a permission assigned to a client, ID1 is the client database ID.
+	ClientPermission
+
+	// ChannelPermission is a permission assigned to a channel, ID1 is the channel ID.
+	ChannelPermission
+
+	// ChannelGroupPermission is a permission assigned to a channel group,
+	// ID1 is the channel group ID and ID2 is the channel ID.
+	ChannelGroupPermission
+
+	// ChannelClientPermission is a permission assigned to a client in a channel,
+	// ID1 is the channel ID and ID2 is the client database ID.
+	ChannelClientPermission
+)
+
+// String implements fmt.Stringer.
+func (t PermissionType) String() string {
+	switch t {
+	case ServerGroupPermission:
+		return "server group"
+	case ClientPermission:
+		return "client"
+	case ChannelPermission:
+		return "channel"
+	case ChannelGroupPermission:
+		return "channel group"
+	case ChannelClientPermission:
+		return "channel client"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(t))
+	}
+}
+
+// PermissionAssignment represents where a permission is assigned as returned by PermFind.
+type PermissionAssignment struct {
+	Type   PermissionType `ms:"t"`
+	ID1    int            `ms:"id1"`
+	ID2    int            `ms:"id2"`
+	PermID int            `ms:"p"`
+	Name   string         `ms:"-"`
+}
+
+// PermFind returns detailed information about all assignments of the permission name.
+func (s *ServerMethods) PermFind(name string) ([]*PermissionAssignment, error) {
+	pc, err := s.Permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := pc.ByName(name)
+	if !ok {
+		return nil, fmt.Errorf("permission: unknown permission %q", name)
+	}
+
+	var assignments []*PermissionAssignment
+	if _, err := s.ExecCmd(NewCmd("permfind").WithArgs(NewArg("permid", p.ID)).WithResponse(&assignments)); err != nil {
+		return nil, err
+	}
+
+	for _, a := range assignments {
+		a.Name = pc.Name(a.PermID)
+	}
+
+	return assignments, nil
+}
+
+// PermissionOverview represents a single entry returned by PermOverview.
+type PermissionOverview struct {
+	Type    PermissionType `ms:"t"`
+	ID1     int            `ms:"id1"`
+	ID2     int            `ms:"id2"`
+	PermID  int            `ms:"p"`
+	Name    string         `ms:"-"`
+	Value   int            `ms:"v"`
+	Negated bool           `ms:"n"`
+	Skip    bool           `ms:"s"`
+}
+
+// PermOverview returns all permissions assigned to the client cldbid in the channel cid.
+// If names is empty, all permissions are returned.
+func (s *ServerMethods) PermOverview(cid, cldbid int, names ...string) ([]*PermissionOverview, error) {
+	pc, err := s.Permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	perms := CmdArg(NewArg("permid", 0))
+	if len(names) > 0 {
+		if perms, err = s.PermissionArgs(names...); err != nil {
+			return nil, err
+		}
+	}
+
+	var overview []*PermissionOverview
+	if _, err := s.ExecCmd(NewCmd("permoverview").WithArgs(
+		NewArg("cid", cid),
+		NewArg("cldbid", cldbid),
+		perms,
+	).WithResponse(&overview)); err != nil {
+		return nil, err
+	}
+
+	for _, o := range overview {
+		o.Name = pc.Name(o.PermID)
+	}
+
+	return overview, nil
+}
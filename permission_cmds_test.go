@@ -0,0 +1,149 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCmdsPermission(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	permissionlist := func(t *testing.T) {
+		t.Helper()
+		perms, err := c.Server.PermissionList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		if !assert.Len(t, perms, 4) {
+			return
+		}
+		expected := &Permission{
+			ID:          2,
+			Name:        "b_serverinstance_version_view",
+			Description: "Retrieve global server version (including platform and build number)",
+		}
+		assert.Equal(t, expected, perms[1])
+	}
+
+	catalog := func(t *testing.T) {
+		t.Helper()
+		pc, err := c.Server.Permissions()
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		p, ok := pc.ByName("i_client_talk_power")
+		if assert.True(t, ok) {
+			assert.Equal(t, 133, p.ID)
+		}
+		p, ok = pc.ByID(167)
+		if assert.True(t, ok) {
+			assert.Equal(t, "b_channel_join_permanent", p.Name)
+		}
+		_, ok = pc.ByName("unknown")
+		assert.False(t, ok)
+		assert.Equal(t, "", pc.Name(-1))
+		assert.Len(t, pc.All(), 4)
+
+		// Subsequent calls use the cached catalog.
+		pc2, err := c.Server.Permissions()
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Same(t, pc, pc2)
+	}
+
+	permidgetbyname := func(t *testing.T) {
+		t.Helper()
+		perms, err := c.Server.PermIDGetByName("i_client_talk_power", "b_channel_join_permanent")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*Permission{
+			{ID: 133, Name: "i_client_talk_power"},
+			{ID: 167, Name: "b_channel_join_permanent"},
+		}
+		assert.Equal(t, expected, perms)
+	}
+
+	permget := func(t *testing.T) {
+		t.Helper()
+		perms, err := c.Server.PermGet("i_client_talk_power")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*PermissionValue{
+			{ID: 133, Name: "i_client_talk_power", Value: 75},
+		}
+		assert.Equal(t, expected, perms)
+	}
+
+	permfind := func(t *testing.T) {
+		t.Helper()
+		assignments, err := c.Server.PermFind("i_client_talk_power")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*PermissionAssignment{
+			{Type: ServerGroupPermission, ID1: 6, PermID: 133, Name: "i_client_talk_power"},
+			{Type: ChannelGroupPermission, ID1: 5, PermID: 133, Name: "i_client_talk_power"},
+		}
+		assert.Equal(t, expected, assignments)
+
+		_, err = c.Server.PermFind("unknown")
+		assert.Error(t, err)
+	}
+
+	permoverview := func(t *testing.T) {
+		t.Helper()
+		overview, err := c.Server.PermOverview(39, 19, "i_client_talk_power")
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*PermissionOverview{
+			{Type: ServerGroupPermission, ID1: 6, PermID: 133, Name: "i_client_talk_power", Value: 50},
+			{Type: ClientPermission, ID1: 19, PermID: 133, Name: "i_client_talk_power", Value: 60, Skip: true},
+			{Type: ChannelGroupPermission, ID1: 5, ID2: 39, PermID: 133, Name: "i_client_talk_power", Value: 10},
+		}
+		assert.Equal(t, expected, overview)
+
+		_, err = c.Server.PermOverview(39, 19, "unknown")
+		assert.Error(t, err)
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"permissionlist", permissionlist},
+		{"catalog", catalog},
+		{"permidgetbyname", permidgetbyname},
+		{"permget", permget},
+		{"permfind", permfind},
+		{"permoverview", permoverview},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestPermissionTypeString(t *testing.T) {
+	assert.Equal(t, "server group", ServerGroupPermission.String())
+	assert.Equal(t, "channel client", ChannelClientPermission.String())
+	assert.Equal(t, "unknown (9)", PermissionType(9).String())
+}
@@ -0,0 +1,116 @@
+package ts3
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Pool is a bounded pool of clients which can be used concurrently.
+//
+// Each client is used by a single caller at a time, so it can select a
+// virtual server with Use without affecting other callers.
+type Pool struct {
+	dial  func() (*Client, error)
+	slots chan struct{} // slots limits the number of clients in use.
+
+	mtx    sync.Mutex
+	idle   []*Client
+	closed bool
+}
+
+// NewPool returns a new Pool of up to size clients created with dial, which
+// should connect and login.
+func NewPool(size int, dial func() (*Client, error)) (*Pool, error) {
+	if size < 1 {
+		return nil, errors.New("new pool: size must be positive")
+	}
+	if dial == nil {
+		return nil, errors.New("new pool: nil dial")
+	}
+
+	return &Pool{
+		dial:  dial,
+		slots: make(chan struct{}, size),
+	}, nil
+}
+
+// Get returns an idle client, dialing a new one if none are available.
+// If the pool is exhausted it waits until a client is returned or ctx is done.
+// The client must be returned to the pool with Put or Discard.
+func (p *Pool) Get(ctx context.Context) (*Client, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mtx.Lock()
+	if p.closed {
+		p.mtx.Unlock()
+		<-p.slots
+		return nil, ErrPoolClosed
+	}
+
+	for len(p.idle) > 0 {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if c.IsConnected() {
+			p.mtx.Unlock()
+			return c, nil
+		}
+		c.Close() //nolint: errcheck
+	}
+	p.mtx.Unlock()
+
+	c, err := p.dial()
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Put returns c to the pool for reuse.
+func (p *Pool) Put(c *Client) {
+	defer func() { <-p.slots }()
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.closed || !c.IsConnected() {
+		c.Close() //nolint: errcheck
+		return
+	}
+
+	p.idle = append(p.idle, c)
+}
+
+// Discard closes c and releases its slot in the pool.
+// Use it instead of Put if the state of c is unknown, for example after a timeout.
+// The slot is released before c is closed, as closing waits for any command c
+// is running, so c may still be in use by another goroutine.
+func (p *Pool) Discard(c *Client) {
+	<-p.slots
+
+	c.Close() //nolint: errcheck
+}
+
+// Close closes the idle clients, clients in use are closed when returned.
+func (p *Pool) Close() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.closed = true
+
+	var err error
+	for _, c := range p.idle {
+		if err2 := c.Close(); err2 != nil && err == nil {
+			err = err2
+		}
+	}
+	p.idle = nil
+
+	return err
+}
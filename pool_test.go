@@ -0,0 +1,68 @@
+package ts3
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	var dials int
+	p, err := NewPool(2, func() (*Client, error) {
+		dials++
+		return NewClient(s.Addr, Timeout(time.Second*2))
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+	c2, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dials)
+
+	// The pool is exhausted.
+	tctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = p.Get(tctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// Returned clients are reused.
+	p.Put(c1)
+	c3, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Same(t, c1, c3)
+	assert.Equal(t, 2, dials)
+
+	// Discarded clients are replaced.
+	p.Discard(c2)
+	assert.False(t, c2.IsConnected())
+	c4, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, dials)
+
+	p.Put(c3)
+	require.NoError(t, p.Close())
+	assert.False(t, c3.IsConnected())
+
+	// Clients in use are closed when returned.
+	p.Put(c4)
+	assert.False(t, c4.IsConnected())
+
+	_, err = p.Get(ctx)
+	assert.Equal(t, ErrPoolClosed, err)
+
+	_, err = NewPool(0, func() (*Client, error) { return nil, nil })
+	assert.Error(t, err)
+	_, err = NewPool(1, nil)
+	assert.Error(t, err)
+}
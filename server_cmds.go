@@ -8,6 +8,8 @@ import (
 const (
 	// ExtendedServerList can be passed to List to get extended server information.
 	ExtendedServerList = "-extended"
+	// ServerListUID can be passed to List to retrieve server unique identifiers.
+	ServerListUID = "-uid"
 
 	// ClientUID can be passed to ClientList to retrieve client UID information.
 	ClientUID = "-uid"
@@ -31,6 +33,9 @@ const (
 	ClientBadges = "-badges"
 	// ClientListFull can be passed to ClientList to get all extended client information.
 	ClientListFull = "-uid -away -voice -times -groups -info -icon -country -ip -badges"
+
+	// ChannelIcon can be passed to ChannelList to retrieve channel icon information.
+	ChannelIcon = "-icon"
 )
 
 // ServerMethods groups server methods.
@@ -328,12 +333,13 @@ type Channel struct {
 	ChannelName          string `ms:"channel_name"`
 	TotalClients         int    `ms:"total_clients"`
 	NeededSubscribePower int    `ms:"channel_needed_subscribe_power"`
+	IconID               int    `ms:"channel_icon_id"` // Only populated if ChannelIcon is passed to ChannelList.
 }
 
 // ChannelList returns a list of channels for the selected server.
-func (s *ServerMethods) ChannelList() ([]*Channel, error) {
+func (s *ServerMethods) ChannelList(options ...string) ([]*Channel, error) {
 	var channels []*Channel
-	if _, err := s.ExecCmd(NewCmd("channellist").WithResponse(&channels)); err != nil {
+	if _, err := s.ExecCmd(NewCmd("channellist").WithOptions(options...).WithResponse(&channels)); err != nil {
 		return nil, err
 	}
 
@@ -343,11 +349,11 @@ func (s *ServerMethods) ChannelList() ([]*Channel, error) {
 // PrivilegeKey represents a server privilege key.
 type PrivilegeKey struct {
 	Token       string
-	Type        int    `ms:"token_type"`
-	ID1         int    `ms:"token_id1"`
-	ID2         int    `ms:"token_id2"`
-	Created     int    `ms:"token_created"`
-	Description string `ms:"token_description"`
+	Type        TokenType `ms:"token_type"`
+	ID1         int       `ms:"token_id1"` // ID1 is the server group ID or the channel group ID.
+	ID2         int       `ms:"token_id2"` // ID2 is the channel ID for channel group tokens.
+	Created     time.Time `ms:"token_created"`
+	Description string    `ms:"token_description"`
 }
 
 // PrivilegeKeyList returns a list of available privilege keys for the selected server,
@@ -364,6 +370,8 @@ func (s *ServerMethods) PrivilegeKeyList() ([]*PrivilegeKey, error) {
 // PrivilegeKeyAdd creates a new privilege token to the selected server and returns it.
 // If tokentype is set to 0, the ID specified with id1 will be a server group ID.
 // Otherwise, id1 is used as a channel group ID and you need to provide a valid channel ID using id2.
+//
+// Deprecated: Use PrivilegeKeyCreate which sets the IDs based on the token type.
 func (s *ServerMethods) PrivilegeKeyAdd(ttype, id1, id2 int, options ...CmdArg) (string, error) {
 	t := struct {
 		Token string
@@ -463,23 +471,31 @@ func (s *ServerMethods) ClientDBList() ([]*DBClient, error) {
 	return dbclients, nil
 }
 
+// Snapshot represents a virtual server snapshot as returned by SnapshotCreate.
 type Snapshot struct {
 	Version string `ms:"version"`
 	Data    string `ms:"data"`
 	Salt    string `ms:"salt"`
 }
 
+// SnapshotCreate creates a snapshot of the selected virtual server encrypted
+// with password. The returned Data has forward slashes escaped.
+//
+// The snapshot must fit in the client parse buffer, see Buffer. Use SnapshotWrite
+// to stream a snapshot to a file without that limit.
 func (s *ServerMethods) SnapshotCreate(password string) (*Snapshot, error) {
-	r := &Snapshot{}
-	if _, err := s.ExecCmd(NewCmd("serversnapshotcreate").
-		WithArgs(NewArg("password", password)).
-		WithResponse(r)); err != nil {
+	r, err := s.snapshotCreate(password)
+	if err != nil {
 		return nil, err
 	}
 	r.Data = strings.NewReplacer(`/`, `\/`).Replace(r.Data)
 	return r, nil
 }
 
+// SnapshotDeploy deploys a snapshot created by SnapshotCreate to the selected
+// virtual server, keeping its files.
+//
+// Use SnapshotDeployFrom to deploy a snapshot file with more control.
 func (s *ServerMethods) SnapshotDeploy(version, data, password, salt string) error {
 	_, err := s.ExecCmd(
 		NewCmd("serversnapshotdeploy").
@@ -0,0 +1,276 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ChangeType is the type of a Change.
+type ChangeType int
+
+const (
+	// Added indicates an item only present in the new snapshot.
+	Added ChangeType = iota + 1
+
+	// Removed indicates an item only present in the old snapshot.
+	Removed
+
+	// Modified indicates an item whose value differs between the snapshots.
+	Modified
+)
+
+// String implements fmt.Stringer.
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(t))
+	}
+}
+
+// Change is a difference between two snapshots.
+type Change struct {
+	Type ChangeType
+	Path string // Path identifies the item, e.g. "channel 5 channel_name".
+	Old  string
+	New  string
+}
+
+// String implements fmt.Stringer.
+func (c *Change) String() string {
+	switch c.Type {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Path, c.New)
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New)
+	}
+}
+
+// differ accumulates changes.
+type differ struct {
+	changes []*Change
+}
+
+// add records a change of the item path from before to after, if any.
+// A nil value indicates the item isn't present.
+func (d *differ) add(path string, before, after *string) {
+	switch {
+	case before == nil && after != nil:
+		d.changes = append(d.changes, &Change{Type: Added, Path: path, New: *after})
+	case before != nil && after == nil:
+		d.changes = append(d.changes, &Change{Type: Removed, Path: path, Old: *before})
+	case before != nil && after != nil && *before != *after:
+		d.changes = append(d.changes, &Change{Type: Modified, Path: path, Old: *before, New: *after})
+	}
+}
+
+// properties records the changes between the properties a and b.
+func (d *differ) properties(path string, a, b Properties) {
+	for _, k := range unionKeys(a, b) {
+		d.add(path+" "+k, lookup(a, k), lookup(b, k))
+	}
+}
+
+// permissions records the changes between the permissions a and b.
+func (d *differ) permissions(path string, a, b []*Permission) {
+	pa, pb := make(Properties), make(Properties)
+	for _, p := range a {
+		pa[p.Name] = p.String()
+	}
+	for _, p := range b {
+		pb[p.Name] = p.String()
+	}
+
+	for _, k := range unionKeys(pa, pb) {
+		d.add(path+" permission "+k, lookup(pa, k), lookup(pb, k))
+	}
+}
+
+// groups records the changes between the groups a and b.
+func (d *differ) groups(kind string, a, b []*Group) {
+	ga, gb := make(map[int]*Group), make(map[int]*Group)
+	for _, g := range a {
+		ga[g.ID] = g
+	}
+	for _, g := range b {
+		gb[g.ID] = g
+	}
+
+	for _, id := range unionIDs(ga, gb) {
+		path := fmt.Sprintf("%s %d", kind, id)
+		oldG, newG := ga[id], gb[id]
+		switch {
+		case newG == nil:
+			d.add(path, &oldG.Name, nil)
+		case oldG == nil:
+			d.add(path, nil, &newG.Name)
+		default:
+			d.add(path+" name", &oldG.Name, &newG.Name)
+			d.properties(path, oldG.Properties, newG.Properties)
+			d.permissions(path, oldG.Permissions, newG.Permissions)
+			d.members(path, oldG.Members, newG.Members)
+		}
+	}
+}
+
+// members records the changes between the group members a and b.
+func (d *differ) members(path string, a, b []*Member) {
+	ma, mb := make(Properties), make(Properties)
+	for _, m := range a {
+		ma[m.String()] = ""
+	}
+	for _, m := range b {
+		mb[m.String()] = ""
+	}
+
+	for _, k := range unionKeys(ma, mb) {
+		k := k
+		var before, after *string
+		if _, ok := ma[k]; ok {
+			before = &k
+		}
+		if _, ok := mb[k]; ok {
+			after = &k
+		}
+		d.add(path+" member", before, after)
+	}
+}
+
+// Diff returns the differences between the snapshots a and b, ordered by section.
+// Channels, clients and groups are matched by ID.
+func Diff(a, b *Snapshot) []*Change {
+	d := &differ{}
+	d.properties("server", a.Server.Properties, b.Server.Properties)
+
+	ca, cb := make(map[int]Properties), make(map[int]Properties)
+	for _, c := range a.Channels {
+		ca[c.ID] = c.Properties
+	}
+	for _, c := range b.Channels {
+		cb[c.ID] = c.Properties
+	}
+	d.items("channel", "channel_name", ca, cb)
+
+	cla, clb := make(map[int]Properties), make(map[int]Properties)
+	for _, c := range a.Clients {
+		cla[c.DatabaseID] = c.Properties
+	}
+	for _, c := range b.Clients {
+		clb[c.DatabaseID] = c.Properties
+	}
+	d.items("client", "client_unique_id", cla, clb)
+
+	d.groups("server group", a.ServerGroups, b.ServerGroups)
+	d.groups("channel group", a.ChannelGroups, b.ChannelGroups)
+
+	for _, id := range unionIDs(a.ChannelPermissions, b.ChannelPermissions) {
+		d.permissions(fmt.Sprintf("channel %d", id), a.ChannelPermissions[id], b.ChannelPermissions[id])
+	}
+	for _, id := range unionIDs(a.ClientPermissions, b.ClientPermissions) {
+		d.permissions(fmt.Sprintf("client %d", id), a.ClientPermissions[id], b.ClientPermissions[id])
+	}
+
+	ids := make(map[ChannelClient]bool)
+	for id := range a.ChannelClientPermissions {
+		ids[id] = true
+	}
+	for id := range b.ChannelClientPermissions {
+		ids[id] = true
+	}
+	ccs := make([]ChannelClient, 0, len(ids))
+	for id := range ids {
+		ccs = append(ccs, id)
+	}
+	sort.Slice(ccs, func(i, j int) bool {
+		if ccs[i].ChannelID != ccs[j].ChannelID {
+			return ccs[i].ChannelID < ccs[j].ChannelID
+		}
+		return ccs[i].DatabaseID < ccs[j].DatabaseID
+	})
+	for _, id := range ccs {
+		path := fmt.Sprintf("channel %d client %d", id.ChannelID, id.DatabaseID)
+		d.permissions(path, a.ChannelClientPermissions[id], b.ChannelClientPermissions[id])
+	}
+
+	return d.changes
+}
+
+// items records the changes between the items a and b, by ID, using the
+// property key to describe added and removed items.
+func (d *differ) items(kind, key string, a, b map[int]Properties) {
+	for _, id := range unionIDs(a, b) {
+		path := fmt.Sprintf("%s %d", kind, id)
+		oldP, newP := a[id], b[id]
+		switch {
+		case newP == nil:
+			d.add(path, lookup(oldP, key), nil)
+		case oldP == nil:
+			d.add(path, nil, lookup(newP, key))
+		default:
+			d.properties(path, oldP, newP)
+		}
+	}
+}
+
+// lookup returns a pointer to the value of key in p or nil if it's not present.
+func lookup(p Properties, key string) *string {
+	v, ok := p[key]
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// unionKeys returns the sorted union of the keys of a and b.
+func unionKeys(a, b Properties) []string {
+	seen := make(map[string]bool, len(a))
+	keys := make([]string, 0, len(a))
+	for _, p := range []Properties{a, b} {
+		for k := range p {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// unionIDs returns the sorted union of the keys of the maps a and b, which
+// must be maps with int keys.
+func unionIDs(a, b interface{}) []int {
+	seen := make(map[int]bool)
+	for _, m := range []interface{}{a, b} {
+		switch m := m.(type) {
+		case map[int]*Group:
+			for id := range m {
+				seen[id] = true
+			}
+		case map[int]Properties:
+			for id := range m {
+				seen[id] = true
+			}
+		case map[int][]*Permission:
+			for id := range m {
+				seen[id] = true
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
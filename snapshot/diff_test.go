@@ -0,0 +1,49 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	a, err := Parse([]byte(sample))
+	require.NoError(t, err)
+
+	assert.Empty(t, Diff(a, a))
+
+	modified := strings.NewReplacer(
+		`virtualserver_maxclients=32`, `virtualserver_maxclients=64`,
+		`channel_id=2 channel_pid=1 channel_name=Match\s1\p2`, `channel_id=3 channel_pid=1 channel_name=Match\s2`,
+		`client_nickname=bob`, `client_nickname=robert`,
+		`permid=i_icon_id permvalue=-5 permskip=0 permnegated=1|`, ``,
+		`cldbid=3 gid=8|`, ``,
+		`client_flat id1=2 permid=i_client_talk_power permvalue=50`, `client_flat id1=2 permid=i_client_talk_power permvalue=75`,
+	).Replace(sample)
+	b, err := Parse([]byte(modified))
+	require.NoError(t, err)
+
+	var changes []string
+	for _, c := range Diff(a, b) {
+		changes = append(changes, c.String())
+	}
+
+	assert.Equal(t, []string{
+		"~ server virtualserver_maxclients: 32 -> 64",
+		"- channel 2: Match 1|2",
+		"+ channel 3: Match 2",
+		"~ client 3 client_nickname: bob -> robert",
+		"- server group 8 permission i_icon_id: i_icon_id=-5 negated=true skip=false",
+		"- server group 8 member: client 3",
+		"~ client 2 permission i_client_talk_power: i_client_talk_power=50 negated=false skip=false -> i_client_talk_power=75 negated=false skip=false",
+	}, changes)
+}
+
+func TestChangeType(t *testing.T) {
+	assert.Equal(t, "added", Added.String())
+	assert.Equal(t, "removed", Removed.String())
+	assert.Equal(t, "modified", Modified.String())
+	assert.Equal(t, "unknown (0)", ChangeType(0).String())
+}
@@ -0,0 +1,290 @@
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+// section is the part of the snapshot being parsed.
+type section int
+
+const (
+	sectionServer section = iota
+	sectionChannels
+	sectionClients
+	sectionPermissions
+	sectionGroups
+	sectionRelations
+	sectionClientFlat
+	sectionChannelFlat
+	sectionChannelClientFlat
+	sectionAPIKeys
+	sectionEnd
+)
+
+// parser holds the state of Parse.
+type parser struct {
+	s       *Snapshot
+	section section
+
+	// groups is the list of server or channel groups being parsed.
+	groups *[]*Group
+
+	// group is the group being parsed.
+	group *Group
+
+	// sticky holds the values which apply to following records until changed.
+	sticky Properties
+}
+
+// Parse parses the decompressed content of a snapshot.
+func Parse(data []byte) (*Snapshot, error) {
+	p := &parser{
+		s: &Snapshot{
+			ClientPermissions:        make(map[int][]*Permission),
+			ChannelPermissions:       make(map[int][]*Permission),
+			ChannelClientPermissions: make(map[ChannelClient][]*Permission),
+		},
+		sticky: make(Properties),
+	}
+
+	for _, record := range strings.Split(strings.TrimSpace(string(data)), "|") {
+		props := make(Properties)
+		for _, token := range strings.Split(record, " ") {
+			if token == "" {
+				continue
+			}
+
+			if p.marker(token) {
+				if err := p.flush(props); err != nil {
+					return nil, err
+				}
+				props = make(Properties)
+				if err := p.transition(token); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			kv := strings.SplitN(token, "=", 2)
+			if len(kv) == 2 {
+				props[ts3.Decode(kv[0])] = ts3.Decode(kv[1])
+			} else {
+				props[ts3.Decode(kv[0])] = ""
+			}
+		}
+
+		if err := p.flush(props); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.s.Server == nil {
+		return nil, fmt.Errorf("snapshot: parse: no server properties: %w", ErrMalformed)
+	}
+
+	return p.s, nil
+}
+
+// markers are the tokens which delimit the sections of a snapshot.
+var markers = map[string]bool{
+	"end_virtualserver":   true,
+	"begin_channels":      true,
+	"end_channels":        true,
+	"begin_clients":       true,
+	"end_clients":         true,
+	"begin_permissions":   true,
+	"end_permissions":     true,
+	"server_groups":       true,
+	"channel_groups":      true,
+	"end_group":           true,
+	"end_groups":          true,
+	"end_relations":       true,
+	"client_flat":         true,
+	"channel_flat":        true,
+	"channel_client_flat": true,
+	"end_flat":            true,
+	"begin_apikeys":       true,
+	"end_apikeys":         true,
+}
+
+// marker returns true if token is a section marker.
+func (p *parser) marker(token string) bool {
+	return markers[token]
+}
+
+// transition changes the section for the marker token.
+func (p *parser) transition(token string) error {
+	next, ok := p.next(token)
+	if !ok {
+		return fmt.Errorf("snapshot: parse: unexpected %q: %w", token, ErrMalformed)
+	}
+
+	p.section = next
+	p.sticky = make(Properties)
+	if token == "end_group" {
+		p.group = nil
+	}
+
+	return nil
+}
+
+// next returns the section which follows the marker token in the current section.
+func (p *parser) next(token string) (section, bool) {
+	switch p.section {
+	case sectionServer:
+		if token == "end_virtualserver" {
+			return sectionEnd, true
+		}
+	case sectionEnd:
+		switch token {
+		case "begin_channels":
+			return sectionChannels, true
+		case "begin_clients":
+			return sectionClients, true
+		case "begin_permissions":
+			return sectionPermissions, true
+		case "begin_apikeys":
+			return sectionAPIKeys, true
+		}
+	case sectionChannels:
+		if token == "end_channels" {
+			return sectionEnd, true
+		}
+	case sectionClients:
+		if token == "end_clients" {
+			return sectionEnd, true
+		}
+	case sectionAPIKeys:
+		if token == "end_apikeys" {
+			return sectionEnd, true
+		}
+	case sectionPermissions:
+		switch token {
+		case "server_groups":
+			p.groups = &p.s.ServerGroups
+			return sectionGroups, true
+		case "channel_groups":
+			p.groups = &p.s.ChannelGroups
+			return sectionGroups, true
+		case "client_flat":
+			return sectionClientFlat, true
+		case "channel_flat":
+			return sectionChannelFlat, true
+		case "channel_client_flat":
+			return sectionChannelClientFlat, true
+		case "end_permissions":
+			return sectionEnd, true
+		}
+	case sectionGroups:
+		switch token {
+		case "end_group":
+			return sectionGroups, true
+		case "end_groups":
+			return sectionRelations, true
+		}
+	case sectionRelations:
+		if token == "end_relations" {
+			return sectionPermissions, true
+		}
+	case sectionClientFlat, sectionChannelFlat, sectionChannelClientFlat:
+		if token == "end_flat" {
+			return sectionPermissions, true
+		}
+	}
+
+	return 0, false
+}
+
+// flush adds the item described by props to the snapshot.
+func (p *parser) flush(props Properties) error {
+	if len(props) == 0 {
+		return nil
+	}
+
+	switch p.section {
+	case sectionServer:
+		p.s.Server = newServer(props)
+	case sectionChannels:
+		p.s.Channels = append(p.s.Channels, newChannel(props))
+	case sectionClients:
+		p.s.Clients = append(p.s.Clients, newClient(props))
+	case sectionGroups:
+		return p.flushGroup(props)
+	case sectionRelations:
+		return p.flushRelation(props)
+	case sectionClientFlat:
+		props = p.stick(props, "id1")
+		id := props.Int("id1")
+		p.s.ClientPermissions[id] = append(p.s.ClientPermissions[id], newPermission(props))
+	case sectionChannelFlat:
+		props = p.stick(props, "id1")
+		id := props.Int("id1")
+		p.s.ChannelPermissions[id] = append(p.s.ChannelPermissions[id], newPermission(props))
+	case sectionChannelClientFlat:
+		props = p.stick(props, "id1", "id2")
+		id := ChannelClient{ChannelID: props.Int("id1"), DatabaseID: props.Int("id2")}
+		p.s.ChannelClientPermissions[id] = append(p.s.ChannelClientPermissions[id], newPermission(props))
+	case sectionAPIKeys:
+		// API keys are secrets which aren't exposed.
+	default:
+		return fmt.Errorf("snapshot: parse: unexpected data in section %d: %w", p.section, ErrMalformed)
+	}
+
+	return nil
+}
+
+// flushGroup adds a group or a permission of the current group.
+func (p *parser) flushGroup(props Properties) error {
+	if _, ok := props["id"]; ok {
+		p.group = &Group{ID: props.Int("id"), Name: props.String("name"), Properties: make(Properties)}
+		*p.groups = append(*p.groups, p.group)
+	}
+
+	if p.group == nil {
+		return fmt.Errorf("snapshot: parse: permission outside group: %w", ErrMalformed)
+	}
+
+	for k, v := range props {
+		switch k {
+		case "id", "name", "permid", "permvalue", "permskip", "permnegated":
+		default:
+			p.group.Properties[k] = v
+		}
+	}
+
+	if _, ok := props["permid"]; ok {
+		p.group.Permissions = append(p.group.Permissions, newPermission(props))
+	}
+
+	return nil
+}
+
+// flushRelation adds a group membership.
+func (p *parser) flushRelation(props Properties) error {
+	props = p.stick(props, "iid")
+	g := findGroup(*p.groups, props.Int("gid"))
+	if g == nil {
+		return fmt.Errorf("snapshot: parse: relation for unknown group %d: %w", props.Int("gid"), ErrMalformed)
+	}
+
+	g.Members = append(g.Members, &Member{DatabaseID: props.Int("cldbid"), ChannelID: props.Int("iid")})
+
+	return nil
+}
+
+// stick returns props with the values of keys from previous records added if
+// not present and records the values of keys present for following records.
+func (p *parser) stick(props Properties, keys ...string) Properties {
+	for _, k := range keys {
+		if v, ok := props[k]; ok {
+			p.sticky[k] = v
+		} else if v, ok := p.sticky[k]; ok {
+			props[k] = v
+		}
+	}
+
+	return props
+}
@@ -0,0 +1,367 @@
+// Package snapshot decodes TeamSpeak 3 virtual server snapshots offline, so
+// backups can be inspected, compared and verified without deploying them.
+//
+// A snapshot file written by ts3.SnapshotWrite can be decoded with:
+//
+//	d, err := snapshot.NewDecoder()
+//	...
+//	snap, err := d.Read(f)
+//
+// Version 3 snapshots are base64 encoded zstd compressed data. Snapshots
+// created with a password are also encrypted, the server doesn't document
+// the encryption scheme so they can't be decoded and Decode returns
+// ErrEncrypted. Create snapshots to be decoded without a password.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultMaxSize is the default maximum decompressed size of a snapshot.
+const DefaultMaxSize = 256 << 20
+
+var (
+	// ErrEncrypted is returned by Decode if the snapshot is encrypted, which
+	// isn't supported.
+	ErrEncrypted = errors.New("snapshot encrypted")
+
+	// ErrMalformed is returned if the snapshot data can't be parsed.
+	ErrMalformed = errors.New("malformed snapshot")
+
+	// zstdMagic is the magic number which starts a zstd frame.
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Properties are the raw properties of an item in a snapshot, decoded but
+// otherwise as stored by the server.
+type Properties map[string]string
+
+// String returns the value of the property key, empty if it's not set.
+func (p Properties) String(key string) string {
+	return p[key]
+}
+
+// Int returns the value of the property key as an int, 0 if it's not set or invalid.
+func (p Properties) Int(key string) int {
+	i, _ := strconv.ParseInt(p[key], 10, 64)
+	return int(i)
+}
+
+// Bool returns the value of the property key as a bool, false if it's not set.
+func (p Properties) Bool(key string) bool {
+	return p[key] == "1"
+}
+
+// Time returns the value of the property key, a unix timestamp, as a time.Time.
+// The zero time is returned if it's not set or invalid.
+func (p Properties) Time(key string) time.Time {
+	if i := p.Int(key); i > 0 {
+		return time.Unix(int64(i), 0)
+	}
+	return time.Time{}
+}
+
+// Server represents the virtual server properties in a snapshot.
+type Server struct {
+	UniqueIdentifier         string
+	Name                     string
+	WelcomeMessage           string
+	HostMessage              string
+	MaxClients               int
+	ReservedSlots            int
+	Created                  time.Time
+	DefaultServerGroup       int
+	DefaultChannelGroup      int
+	DefaultChannelAdminGroup int
+	IconID                   int
+	Properties               Properties
+}
+
+// newServer returns the Server for the properties p.
+func newServer(p Properties) *Server {
+	return &Server{
+		UniqueIdentifier:         p.String("virtualserver_unique_identifier"),
+		Name:                     p.String("virtualserver_name"),
+		WelcomeMessage:           p.String("virtualserver_welcomemessage"),
+		HostMessage:              p.String("virtualserver_hostmessage"),
+		MaxClients:               p.Int("virtualserver_maxclients"),
+		ReservedSlots:            p.Int("virtualserver_reserved_slots"),
+		Created:                  p.Time("virtualserver_created"),
+		DefaultServerGroup:       p.Int("virtualserver_default_server_group"),
+		DefaultChannelGroup:      p.Int("virtualserver_default_channel_group"),
+		DefaultChannelAdminGroup: p.Int("virtualserver_default_channel_admin_group"),
+		IconID:                   p.Int("virtualserver_icon_id"),
+		Properties:               p,
+	}
+}
+
+// Channel represents a channel in a snapshot.
+type Channel struct {
+	ID               int
+	ParentID         int
+	Name             string
+	Topic            string
+	Description      string
+	Order            int
+	MaxClients       int
+	Codec            int
+	CodecQuality     int
+	Permanent        bool
+	SemiPermanent    bool
+	Default          bool
+	HasPassword      bool
+	UniqueIdentifier string
+	Properties       Properties
+}
+
+// newChannel returns the Channel for the properties p.
+func newChannel(p Properties) *Channel {
+	return &Channel{
+		ID:               p.Int("channel_id"),
+		ParentID:         p.Int("channel_pid"),
+		Name:             p.String("channel_name"),
+		Topic:            p.String("channel_topic"),
+		Description:      p.String("channel_description"),
+		Order:            p.Int("channel_order"),
+		MaxClients:       p.Int("channel_maxclients"),
+		Codec:            p.Int("channel_codec"),
+		CodecQuality:     p.Int("channel_codec_quality"),
+		Permanent:        p.Bool("channel_flag_permanent"),
+		SemiPermanent:    p.Bool("channel_flag_semi_permanent"),
+		Default:          p.Bool("channel_flag_default"),
+		HasPassword:      p.Bool("channel_flag_password"),
+		UniqueIdentifier: p.String("channel_unique_identifier"),
+		Properties:       p,
+	}
+}
+
+// Client represents a client identity known by the server in a snapshot.
+type Client struct {
+	DatabaseID       int
+	UniqueIdentifier string
+	Nickname         string
+	Description      string
+	Created          time.Time
+	LastConnected    time.Time
+	TotalConnections int
+	Properties       Properties
+}
+
+// newClient returns the Client for the properties p.
+func newClient(p Properties) *Client {
+	return &Client{
+		DatabaseID:       p.Int("client_id"),
+		UniqueIdentifier: p.String("client_unique_id"),
+		Nickname:         p.String("client_nickname"),
+		Description:      p.String("client_description"),
+		Created:          p.Time("client_created"),
+		LastConnected:    p.Time("client_lastconnected"),
+		TotalConnections: p.Int("client_totalconnections"),
+		Properties:       p,
+	}
+}
+
+// Permission represents a permission assignment in a snapshot.
+type Permission struct {
+	Name    string
+	Value   int
+	Negated bool
+	Skip    bool
+}
+
+// String implements fmt.Stringer.
+func (p *Permission) String() string {
+	return fmt.Sprintf("%s=%d negated=%t skip=%t", p.Name, p.Value, p.Negated, p.Skip)
+}
+
+// newPermission returns the Permission for the properties p.
+func newPermission(p Properties) *Permission {
+	return &Permission{
+		Name:    p.String("permid"),
+		Value:   p.Int("permvalue"),
+		Negated: p.Bool("permnegated"),
+		Skip:    p.Bool("permskip"),
+	}
+}
+
+// Member represents the membership of a client in a group.
+type Member struct {
+	DatabaseID int
+	ChannelID  int // ChannelID is the channel of a channel group membership, 0 for server groups.
+}
+
+// String implements fmt.Stringer.
+func (m *Member) String() string {
+	if m.ChannelID != 0 {
+		return fmt.Sprintf("client %d in channel %d", m.DatabaseID, m.ChannelID)
+	}
+	return fmt.Sprintf("client %d", m.DatabaseID)
+}
+
+// Group represents a server or channel group in a snapshot.
+type Group struct {
+	ID          int
+	Name        string
+	Permissions []*Permission
+	Members     []*Member
+	Properties  Properties
+}
+
+// ChannelClient identifies a client in a channel.
+type ChannelClient struct {
+	ChannelID  int
+	DatabaseID int
+}
+
+// Snapshot is a decoded virtual server snapshot.
+type Snapshot struct {
+	Version       int
+	Server        *Server
+	Channels      []*Channel
+	Clients       []*Client
+	ServerGroups  []*Group
+	ChannelGroups []*Group
+
+	// ClientPermissions are the permissions of clients by database ID.
+	ClientPermissions map[int][]*Permission
+
+	// ChannelPermissions are the permissions of channels by channel ID.
+	ChannelPermissions map[int][]*Permission
+
+	// ChannelClientPermissions are the permissions of clients in channels.
+	ChannelClientPermissions map[ChannelClient][]*Permission
+}
+
+// Channel returns the channel cid or nil if it doesn't exist.
+func (s *Snapshot) Channel(cid int) *Channel {
+	for _, c := range s.Channels {
+		if c.ID == cid {
+			return c
+		}
+	}
+	return nil
+}
+
+// Client returns the client cldbid or nil if it doesn't exist.
+func (s *Snapshot) Client(cldbid int) *Client {
+	for _, c := range s.Clients {
+		if c.DatabaseID == cldbid {
+			return c
+		}
+	}
+	return nil
+}
+
+// ServerGroup returns the server group sgid or nil if it doesn't exist.
+func (s *Snapshot) ServerGroup(sgid int) *Group {
+	return findGroup(s.ServerGroups, sgid)
+}
+
+// ChannelGroup returns the channel group cgid or nil if it doesn't exist.
+func (s *Snapshot) ChannelGroup(cgid int) *Group {
+	return findGroup(s.ChannelGroups, cgid)
+}
+
+// findGroup returns the group id from groups or nil if it doesn't exist.
+func findGroup(groups []*Group, id int) *Group {
+	for _, g := range groups {
+		if g.ID == id {
+			return g
+		}
+	}
+	return nil
+}
+
+// Decoder decodes snapshots.
+type Decoder struct {
+	maxSize uint64
+}
+
+// MaxSize sets the maximum decompressed size of a snapshot, default DefaultMaxSize.
+func MaxSize(n uint64) func(*Decoder) error {
+	return func(d *Decoder) error {
+		d.maxSize = n
+		return nil
+	}
+}
+
+// NewDecoder returns a new Decoder.
+func NewDecoder(options ...func(*Decoder) error) (*Decoder, error) {
+	d := &Decoder{maxSize: DefaultMaxSize}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(d); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// Read reads a snapshot file written by ts3.SnapshotWrite from r and decodes it.
+func (d *Decoder) Read(r io.Reader) (*Snapshot, error) {
+	f, err := ts3.SnapshotRead(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Decode(f)
+}
+
+// Decode decodes the snapshot file f.
+func (d *Decoder) Decode(f *ts3.SnapshotFile) (*Snapshot, error) {
+	data, err := d.content(f)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	s.Version = f.Version
+
+	return s, nil
+}
+
+// content returns the decompressed content of f.
+func (d *Decoder) content(f *ts3.SnapshotFile) ([]byte, error) {
+	// Snapshots before version 3 weren't compressed.
+	if strings.HasPrefix(f.Data, "virtualserver_") {
+		return []byte(f.Data), nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(f.Data)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: decode base64: %v: %w", err, ErrMalformed)
+	}
+
+	if !bytes.HasPrefix(data, zstdMagic) {
+		// Compressed data which isn't a zstd frame was encrypted with a password.
+		return nil, fmt.Errorf("snapshot: decode: %w", ErrEncrypted)
+	}
+
+	dec, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(d.maxSize), zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: decompress: %w", err)
+	}
+	defer dec.Close()
+
+	if data, err = dec.DecodeAll(data, nil); err != nil {
+		return nil, fmt.Errorf("snapshot: decompress: %w", err)
+	}
+
+	return data, nil
+}
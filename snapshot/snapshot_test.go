@@ -0,0 +1,194 @@
+package snapshot
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sample is the content of a small snapshot.
+const sample = `virtualserver_unique_identifier=nGx8L+dlsORPdxeniVOkGyHutuA= virtualserver_name=Test\sServer ` +
+	`virtualserver_password virtualserver_maxclients=32 virtualserver_created=1719842376 ` +
+	`virtualserver_default_server_group=8 virtualserver_default_channel_group=9 ` +
+	`virtualserver_default_channel_admin_group=5 virtualserver_icon_id=3767521056 end_virtualserver|` +
+	`begin_channels channel_id=1 channel_pid=0 channel_name=Lobby channel_topic channel_flag_default=1 channel_flag_permanent=1|` +
+	`channel_id=2 channel_pid=1 channel_name=Match\s1\p2 channel_order=1 channel_maxclients=-1 channel_flag_password=1|` +
+	`end_channels|` +
+	`begin_clients client_id=2 client_unique_id=uid2 client_nickname=alice client_created=1 ` +
+	`client_lastconnected=1700000000 client_totalconnections=3 client_description=\s|` +
+	`client_id=3 client_unique_id=uid3 client_nickname=bob|end_clients|` +
+	`begin_permissions|` +
+	`server_groups id=8 name=Guest permid=b_virtualserver_info_view permvalue=1 permskip=0 permnegated=0|` +
+	`permid=i_icon_id permvalue=-5 permskip=0 permnegated=1|end_group|` +
+	`id=6 name=Admin permid=b_serverinstance_help_view permvalue=1 permskip=1 permnegated=0|end_group|end_groups|` +
+	`iid=0 cldbid=2 gid=8|cldbid=3 gid=6|cldbid=3 gid=8|end_relations|` +
+	`channel_groups id=5 name=Channel\sAdmin permid=i_channel_join_power permvalue=75 permskip=0 permnegated=0|end_group|` +
+	`id=9 name=Guest permid=i_channel_join_power permvalue=1 permskip=0 permnegated=0|end_group|end_groups|` +
+	`iid=2 cldbid=2 gid=5|cldbid=3 gid=9|iid=1 cldbid=3 gid=5|end_relations|` +
+	`client_flat id1=2 permid=i_client_talk_power permvalue=50 permskip=0 permnegated=0|end_flat|` +
+	`channel_flat id1=2 id2=0 permid=i_channel_needed_join_power permvalue=50 permskip=0 permnegated=0|` +
+	`permid=i_icon_id permvalue=7 permskip=0 permnegated=0|end_flat|` +
+	`channel_client_flat id1=2 id2=3 permid=i_client_needed_talk_power permvalue=10 permskip=0 permnegated=0|end_flat|` +
+	`end_permissions|begin_apikeys end_apikeys`
+
+// compress returns data compressed with zstd.
+func compress(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer enc.Close() //nolint: errcheck
+
+	return enc.EncodeAll(data, nil)
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(sample))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Test Server", s.Server.Name)
+	assert.Equal(t, "nGx8L+dlsORPdxeniVOkGyHutuA=", s.Server.UniqueIdentifier)
+	assert.Equal(t, 32, s.Server.MaxClients)
+	assert.Equal(t, time.Unix(1719842376, 0), s.Server.Created)
+	assert.Equal(t, 3767521056, s.Server.IconID)
+	assert.Equal(t, 8, s.Server.DefaultServerGroup)
+	assert.Equal(t, "", s.Server.Properties["virtualserver_password"])
+
+	require.Len(t, s.Channels, 2)
+	assert.True(t, s.Channels[0].Default)
+	assert.True(t, s.Channels[0].Permanent)
+	assert.Equal(t, "Match 1|2", s.Channels[1].Name)
+	assert.Equal(t, 1, s.Channels[1].ParentID)
+	assert.Equal(t, -1, s.Channels[1].MaxClients)
+	assert.True(t, s.Channels[1].HasPassword)
+	assert.Equal(t, s.Channels[1], s.Channel(2))
+	assert.Nil(t, s.Channel(3))
+
+	require.Len(t, s.Clients, 2)
+	assert.Equal(t, &Client{
+		DatabaseID:       2,
+		UniqueIdentifier: "uid2",
+		Nickname:         "alice",
+		Description:      " ",
+		Created:          time.Unix(1, 0),
+		LastConnected:    time.Unix(1700000000, 0),
+		TotalConnections: 3,
+		Properties:       s.Clients[0].Properties,
+	}, s.Client(2))
+
+	require.Len(t, s.ServerGroups, 2)
+	guest := s.ServerGroup(8)
+	require.NotNil(t, guest)
+	assert.Equal(t, "Guest", guest.Name)
+	assert.Equal(t, []*Permission{
+		{Name: "b_virtualserver_info_view", Value: 1},
+		{Name: "i_icon_id", Value: -5, Negated: true},
+	}, guest.Permissions)
+	assert.Equal(t, []*Member{{DatabaseID: 2}, {DatabaseID: 3}}, guest.Members)
+	assert.Equal(t, []*Permission{{Name: "b_serverinstance_help_view", Value: 1, Skip: true}}, s.ServerGroup(6).Permissions)
+
+	require.Len(t, s.ChannelGroups, 2)
+	assert.Equal(t, []*Member{{DatabaseID: 2, ChannelID: 2}, {DatabaseID: 3, ChannelID: 1}}, s.ChannelGroup(5).Members)
+	assert.Equal(t, []*Member{{DatabaseID: 3, ChannelID: 2}}, s.ChannelGroup(9).Members)
+
+	assert.Equal(t, map[int][]*Permission{2: {{Name: "i_client_talk_power", Value: 50}}}, s.ClientPermissions)
+	assert.Equal(t, map[int][]*Permission{2: {
+		{Name: "i_channel_needed_join_power", Value: 50},
+		{Name: "i_icon_id", Value: 7},
+	}}, s.ChannelPermissions)
+	assert.Equal(t, map[ChannelClient][]*Permission{
+		{ChannelID: 2, DatabaseID: 3}: {{Name: "i_client_needed_talk_power", Value: 10}},
+	}, s.ChannelClientPermissions)
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"no-server", "begin_channels channel_id=1|end_channels"},
+		{"unexpected-marker", "virtualserver_name=x end_virtualserver|end_channels"},
+		{"unknown-group", "virtualserver_name=x end_virtualserver|begin_permissions|server_groups end_groups|cldbid=1 gid=2|end_relations"},
+		{"permission-outside-group", "virtualserver_name=x end_virtualserver|begin_permissions|server_groups permid=x"},
+		{"data-outside-section", "virtualserver_name=x end_virtualserver|channel_id=1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			assert.True(t, errors.Is(err, ErrMalformed), "%v", err)
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	_, err := NewDecoder(nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+
+	d, err := NewDecoder()
+	require.NoError(t, err)
+
+	compressed := compress(t, []byte(sample))
+	encoded := base64.StdEncoding.EncodeToString(compressed)
+
+	s, err := d.Decode(&ts3.SnapshotFile{Format: ts3.SnapshotFormat, Version: 3, Data: encoded})
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Version)
+	assert.Equal(t, "Test Server", s.Server.Name)
+
+	// Snapshots before version 3 are plain text.
+	s, err = d.Decode(&ts3.SnapshotFile{Format: ts3.SnapshotFormat, Data: sample})
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.Version)
+	assert.Len(t, s.Channels, 2)
+
+	_, err = d.Decode(&ts3.SnapshotFile{Format: ts3.SnapshotFormat, Version: 3, Data: "!!"})
+	assert.True(t, errors.Is(err, ErrMalformed))
+
+	// Encrypted data isn't a zstd frame.
+	encrypted := &ts3.SnapshotFile{
+		Format:  ts3.SnapshotFormat,
+		Version: 3,
+		Salt:    "c2FsdA==",
+		Data:    base64.StdEncoding.EncodeToString([]byte("\x8a\x13\x5c\xe0encrypted")),
+	}
+	_, err = d.Decode(encrypted)
+	assert.True(t, errors.Is(err, ErrEncrypted))
+
+	d, err = NewDecoder(MaxSize(64))
+	require.NoError(t, err)
+	_, err = d.Decode(&ts3.SnapshotFile{Format: ts3.SnapshotFormat, Version: 3, Data: encoded})
+	assert.Error(t, err)
+}
+
+func TestDecoderRead(t *testing.T) {
+	f, err := os.Open("testdata/snapshot.json")
+	require.NoError(t, err)
+	defer f.Close() //nolint: errcheck
+
+	d, err := NewDecoder()
+	require.NoError(t, err)
+
+	s, err := d.Read(f)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, s.Version)
+	assert.Equal(t, "nGx8L+dlsORPdxeniVOkGyHutuA=", s.Server.UniqueIdentifier)
+	assert.Equal(t, "Barbecue party", s.Server.Properties.String("virtualserver_name_phonetic"))
+	assert.Len(t, s.Channels, 23)
+	assert.Len(t, s.Clients, 70)
+	assert.Len(t, s.ServerGroups, 8)
+	assert.Len(t, s.ChannelGroups, 2)
+	assert.Equal(t, 25, s.Channel(27).ParentID)
+	assert.Len(t, s.ChannelPermissions, 23)
+	assert.Empty(t, s.ClientPermissions)
+	assert.NoError(t, s.Verify())
+}
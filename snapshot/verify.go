@@ -0,0 +1,97 @@
+package snapshot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VerifyError is returned by Verify and lists the problems found.
+type VerifyError struct {
+	Problems []string
+}
+
+// Error implements error.
+func (e *VerifyError) Error() string {
+	return "snapshot: verify: " + strings.Join(e.Problems, ", ")
+}
+
+// Verify checks the consistency of the snapshot: channel and group IDs are
+// unique, channel parents exist, there is a single default channel, the default
+// groups exist and group members reference known clients and channels.
+// If problems are found the returned error is a *VerifyError.
+func (s *Snapshot) Verify() error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if s.Server.UniqueIdentifier == "" {
+		addf("no server unique identifier")
+	}
+
+	channels := make(map[int]bool, len(s.Channels))
+	var defaults int
+	for _, c := range s.Channels {
+		if channels[c.ID] {
+			addf("duplicate channel %d", c.ID)
+		}
+		channels[c.ID] = true
+		if c.Default {
+			defaults++
+		}
+	}
+	if defaults != 1 {
+		addf("%d default channels", defaults)
+	}
+	for _, c := range s.Channels {
+		if c.ParentID != 0 && !channels[c.ParentID] {
+			addf("channel %d has unknown parent %d", c.ID, c.ParentID)
+		}
+	}
+
+	clients := make(map[int]bool, len(s.Clients))
+	for _, c := range s.Clients {
+		clients[c.DatabaseID] = true
+	}
+
+	for _, g := range []struct {
+		kind   string
+		groups []*Group
+	}{
+		{"server group", s.ServerGroups},
+		{"channel group", s.ChannelGroups},
+	} {
+		seen := make(map[int]bool, len(g.groups))
+		for _, grp := range g.groups {
+			if seen[grp.ID] {
+				addf("duplicate %s %d", g.kind, grp.ID)
+			}
+			seen[grp.ID] = true
+
+			for _, m := range grp.Members {
+				if !clients[m.DatabaseID] {
+					addf("%s %d has unknown client %d", g.kind, grp.ID, m.DatabaseID)
+				}
+				if m.ChannelID != 0 && !channels[m.ChannelID] {
+					addf("%s %d has unknown channel %d", g.kind, grp.ID, m.ChannelID)
+				}
+			}
+		}
+	}
+
+	if sgid := s.Server.DefaultServerGroup; s.ServerGroup(sgid) == nil {
+		addf("unknown default server group %d", sgid)
+	}
+	if cgid := s.Server.DefaultChannelGroup; s.ChannelGroup(cgid) == nil {
+		addf("unknown default channel group %d", cgid)
+	}
+	if cgid := s.Server.DefaultChannelAdminGroup; s.ChannelGroup(cgid) == nil {
+		addf("unknown default channel admin group %d", cgid)
+	}
+
+	if len(problems) > 0 {
+		return &VerifyError{Problems: problems}
+	}
+
+	return nil
+}
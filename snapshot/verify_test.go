@@ -0,0 +1,38 @@
+package snapshot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	s, err := Parse([]byte(sample))
+	require.NoError(t, err)
+	assert.NoError(t, s.Verify())
+
+	broken := strings.NewReplacer(
+		`channel_pid=1`, `channel_pid=7`,
+		`channel_flag_default=1`, `channel_flag_default=0`,
+		`virtualserver_default_server_group=8`, `virtualserver_default_server_group=99`,
+		`iid=2 cldbid=2 gid=5`, `iid=4 cldbid=5 gid=5`,
+	).Replace(sample)
+	s, err = Parse([]byte(broken))
+	require.NoError(t, err)
+
+	err = s.Verify()
+	var verr *VerifyError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, []string{
+		"0 default channels",
+		"channel 2 has unknown parent 7",
+		"channel group 5 has unknown client 5",
+		"channel group 5 has unknown channel 4",
+		"channel group 9 has unknown channel 4",
+		"unknown default server group 99",
+	}, verr.Problems)
+	assert.Contains(t, err.Error(), "unknown parent 7")
+}
@@ -0,0 +1,358 @@
+package ts3
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotFormat identifies the snapshot file format written by SnapshotWrite.
+const SnapshotFormat = "ts3-snapshot"
+
+// SnapshotFile is the self describing JSON envelope written by SnapshotWrite
+// and read by SnapshotRead.
+type SnapshotFile struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"` // Version is the snapshot version, 0 for servers before 3.10.
+	Salt    string    `json:"salt,omitempty"`
+	Created time.Time `json:"created"`
+	Data    string    `json:"data"` // Data is the base64 encoded snapshot.
+}
+
+// SnapshotRead reads a snapshot file written by SnapshotWrite from r.
+func SnapshotRead(r io.Reader) (*SnapshotFile, error) {
+	f := &SnapshotFile{}
+	if err := json.NewDecoder(r).Decode(f); err != nil {
+		return nil, fmt.Errorf("snapshot read: %w", err)
+	}
+
+	if f.Format != SnapshotFormat {
+		return nil, fmt.Errorf("snapshot read: format %q: %w", f.Format, ErrInvalidSnapshot)
+	}
+
+	if f.Data == "" {
+		return nil, fmt.Errorf("snapshot read: no data: %w", ErrInvalidSnapshot)
+	}
+
+	return f, nil
+}
+
+// snapshotCreate creates a snapshot of the selected virtual server.
+func (s *ServerMethods) snapshotCreate(password string) (*Snapshot, error) {
+	r := &Snapshot{}
+	if _, err := s.ExecCmd(NewCmd("serversnapshotcreate").
+		WithArgs(NewArg("password", password)).
+		WithResponse(r)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// snapshotFile creates a snapshot of the selected virtual server encrypted
+// with password and returns it as a SnapshotFile.
+func (s *ServerMethods) snapshotFile(password string) (*SnapshotFile, error) {
+	var buf bytes.Buffer
+	if err := s.SnapshotWrite(&buf, password); err != nil {
+		return nil, err
+	}
+
+	return SnapshotRead(&buf)
+}
+
+// SnapshotWrite creates a snapshot of the selected virtual server encrypted
+// with password and writes it to w as a SnapshotFile.
+//
+// The snapshot is streamed to w as it's received, so unlike SnapshotCreate
+// it isn't limited by the client parse buffer.
+func (s *ServerMethods) SnapshotWrite(w io.Writer, password string) error {
+	sw := newSnapshotWriter(w, time.Now().UTC().Truncate(time.Second))
+	if _, err := s.ExecCmd(NewCmd("serversnapshotcreate").
+		WithArgs(NewArg("password", password)).
+		WithStream(sw)); err != nil {
+		return err
+	}
+
+	if err := sw.Close(); err != nil {
+		return fmt.Errorf("snapshot write: %w", err)
+	}
+
+	return nil
+}
+
+// snapshotWriter converts a streamed serversnapshotcreate response to a
+// SnapshotFile. The data is written as it's received with the other fields,
+// which may follow it, written once the response is complete.
+type snapshotWriter struct {
+	w       *bufio.Writer
+	created time.Time
+	fields  map[string]string
+
+	key     []byte // key is the key being read.
+	value   []byte // value is the value being read, unless it's the data.
+	inValue bool
+	inData  bool
+	escape  bool // escape is true if the last data byte was a backslash.
+	data    bool // data is true once the data has been read.
+	err     error
+}
+
+// newSnapshotWriter returns a snapshotWriter which writes to w.
+func newSnapshotWriter(w io.Writer, created time.Time) *snapshotWriter {
+	return &snapshotWriter{
+		w:       bufio.NewWriter(w),
+		created: created,
+		fields:  make(map[string]string),
+	}
+}
+
+// Write implements io.Writer.
+func (sw *snapshotWriter) Write(p []byte) (int, error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+
+	for _, b := range p {
+		if sw.err = sw.writeByte(b); sw.err != nil {
+			return 0, sw.err
+		}
+	}
+
+	return len(p), nil
+}
+
+// writeByte processes the response byte b.
+func (sw *snapshotWriter) writeByte(b byte) error {
+	end := b == ' ' || b == '|' || b == '\n'
+	switch {
+	case !sw.inValue && b == '=':
+		sw.inValue = true
+		sw.inData = string(sw.key) == "data"
+		if !sw.inData {
+			return nil
+		}
+		if sw.data {
+			return fmt.Errorf("duplicate data: %w", ErrInvalidSnapshot)
+		}
+
+		created, err := json.Marshal(sw.created)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(sw.w, `{"format":%q,"created":%s,"data":"`, SnapshotFormat, created)
+		return err
+	case !sw.inValue:
+		if end {
+			sw.key = sw.key[:0]
+		} else {
+			sw.key = append(sw.key, b)
+		}
+		return nil
+	case end:
+		return sw.endValue()
+	case !sw.inData:
+		sw.value = append(sw.value, b)
+		return nil
+	case sw.escape:
+		sw.escape = false
+		return sw.writeData(Decode(string([]byte{'\\', b})))
+	case b == '\\':
+		sw.escape = true
+		return nil
+	default:
+		return sw.writeDataByte(b)
+	}
+}
+
+// writeData writes the decoded data s as part of a JSON string.
+func (sw *snapshotWriter) writeData(s string) error {
+	for i := 0; i < len(s); i++ {
+		if err := sw.writeDataByte(s[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeDataByte writes the decoded data byte b as part of a JSON string.
+func (sw *snapshotWriter) writeDataByte(b byte) error {
+	switch {
+	case b == '"' || b == '\\':
+		_, err := sw.w.Write([]byte{'\\', b})
+		return err
+	case b < 0x20:
+		_, err := fmt.Fprintf(sw.w, `\u%04x`, b)
+		return err
+	default:
+		return sw.w.WriteByte(b)
+	}
+}
+
+// endValue completes the value being read.
+func (sw *snapshotWriter) endValue() error {
+	if sw.inData {
+		sw.data = true
+		if err := sw.w.WriteByte('"'); err != nil {
+			return err
+		}
+	} else {
+		sw.fields[string(sw.key)] = Decode(string(sw.value))
+	}
+
+	sw.key = sw.key[:0]
+	sw.value = sw.value[:0]
+	sw.inValue, sw.inData, sw.escape = false, false, false
+
+	return nil
+}
+
+// Close completes the SnapshotFile.
+func (sw *snapshotWriter) Close() error {
+	if sw.err != nil {
+		return sw.err
+	}
+
+	if sw.inValue {
+		if err := sw.endValue(); err != nil {
+			return err
+		}
+	}
+
+	if !sw.data {
+		return fmt.Errorf("no data: %w", ErrInvalidSnapshot)
+	}
+
+	if v, ok := sw.fields["version"]; ok {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("version %q: %w", v, err)
+		}
+		fmt.Fprintf(sw.w, `,"version":%d`, version)
+	}
+
+	if salt := sw.fields["salt"]; salt != "" {
+		q, err := json.Marshal(salt)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(sw.w, `,"salt":%s`, q)
+	}
+
+	if _, err := sw.w.WriteString("}\n"); err != nil {
+		return err
+	}
+
+	return sw.w.Flush()
+}
+
+// DeployOptions controls how SnapshotDeployFrom deploys a snapshot.
+type DeployOptions struct {
+	// KeepFiles keeps the existing files of the virtual server, otherwise
+	// the file structure of the snapshot is created empty.
+	KeepFiles bool
+
+	// Mapping requests the mapping of the IDs in the snapshot to the IDs
+	// created by the deploy.
+	Mapping bool
+}
+
+// DeployResult is the result of SnapshotDeployFrom.
+type DeployResult struct {
+	// ServerID is the ID of the virtual server the snapshot was deployed to,
+	// if returned by the server.
+	ServerID int
+
+	// Channels maps the channel IDs in the snapshot to the deployed channel IDs.
+	// Only populated if Mapping was requested.
+	Channels map[int]int
+}
+
+// SnapshotDeployFrom reads a snapshot file written by SnapshotWrite from r and
+// deploys it to the selected virtual server. The password must match the one
+// used to create the snapshot. If opts is nil the defaults are used.
+func (s *ServerMethods) SnapshotDeployFrom(r io.Reader, password string, opts *DeployOptions) (*DeployResult, error) {
+	f, err := SnapshotRead(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SnapshotDeployFile(f, password, opts)
+}
+
+// SnapshotDeployFile deploys the snapshot file f to the selected virtual server.
+// The password must match the one used to create the snapshot. If opts is nil
+// the defaults are used.
+func (s *ServerMethods) SnapshotDeployFile(f *SnapshotFile, password string, opts *DeployOptions) (*DeployResult, error) {
+	if opts == nil {
+		opts = &DeployOptions{}
+	}
+
+	var options []string
+	if opts.KeepFiles {
+		options = append(options, "-keepfiles")
+	}
+	if opts.Mapping {
+		options = append(options, "-mapping")
+	}
+
+	args := []CmdArg{NewArg("password", password)}
+	if f.Version != 0 {
+		args = append(args, NewArg("version", f.Version))
+	}
+	if f.Salt != "" {
+		args = append(args, NewArg("salt", f.Salt))
+	}
+	args = append(args, NewArg("data", f.Data))
+
+	lines, err := s.ExecCmd(NewCmd("serversnapshotdeploy").WithOptions(options...).WithArgs(args...))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDeployResult(lines), nil
+}
+
+// parseDeployResult returns the DeployResult from the serversnapshotdeploy response lines.
+func parseDeployResult(lines []string) *DeployResult {
+	res := &DeployResult{}
+	for _, line := range lines {
+		for _, record := range strings.Split(line, "|") {
+			var ocid, ncid int
+			for _, kv := range strings.Split(record, " ") {
+				parts := strings.SplitN(kv, "=", 2)
+				if len(parts) != 2 {
+					continue
+				}
+
+				v, err := strconv.Atoi(parts[1])
+				if err != nil {
+					continue
+				}
+
+				switch parts[0] {
+				case "sid":
+					res.ServerID = v
+				case "ocid":
+					ocid = v
+				case "ncid":
+					ncid = v
+				}
+			}
+
+			if ocid != 0 && ncid != 0 {
+				if res.Channels == nil {
+					res.Channels = make(map[int]int)
+				}
+				res.Channels[ocid] = ncid
+			}
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,179 @@
+package ts3
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRead(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   bool
+	}{
+		{"valid", `{"format":"ts3-snapshot","version":3,"salt":"c2FsdA==","created":"2020-01-02T03:04:05Z","data":"KLUv/abc"}`, false},
+		{"format", `{"format":"other","version":3,"data":"KLUv/abc"}`, true},
+		{"no-data", `{"format":"ts3-snapshot","version":3}`, true},
+		{"json", `version=3 data=KLUv\/abc`, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := SnapshotRead(strings.NewReader(tc.input))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &SnapshotFile{
+				Format:  SnapshotFormat,
+				Version: 3,
+				Salt:    "c2FsdA==",
+				Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				Data:    "KLUv/abc",
+			}, f)
+		})
+	}
+
+	_, err := SnapshotRead(strings.NewReader(`{"format":"other","data":"KLUv/abc"}`))
+	assert.True(t, errors.Is(err, ErrInvalidSnapshot))
+}
+
+func TestSnapshotWriter(t *testing.T) {
+	var buf bytes.Buffer
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	sw := newSnapshotWriter(&buf, created)
+
+	// Written a byte at a time so escapes are split, with the data first.
+	for _, b := range []byte(`data=KLUv\/ab\/c salt=c2FsdA== version=3`) {
+		n, err := sw.Write([]byte{b})
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	}
+	require.NoError(t, sw.Close())
+
+	f, err := SnapshotRead(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, &SnapshotFile{
+		Format:  SnapshotFormat,
+		Version: 3,
+		Salt:    "c2FsdA==",
+		Created: created,
+		Data:    "KLUv/ab/c",
+	}, f)
+
+	sw = newSnapshotWriter(&buf, created)
+	_, err = sw.Write([]byte("version=3 salt=c2FsdA=="))
+	require.NoError(t, err)
+	assert.True(t, errors.Is(sw.Close(), ErrInvalidSnapshot))
+}
+
+func TestSnapshotWriteLarge(t *testing.T) {
+	// Larger than the maximum parse buffer, so it must be streamed.
+	data := strings.Repeat("KLUv/aTFeAEAjeAA", MaxParseTokenSize/16+1<<16)
+	resp := `version=3 salt=c2FsdA== data=` + strings.ReplaceAll(data, "/", `\/`)
+	s := newServer(t, handler("serversnapshotcreate", func(string) string {
+		return resp
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*5))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Server.SnapshotWrite(&buf, "secret"))
+
+	f, err := SnapshotRead(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, f.Version)
+	assert.Equal(t, "c2FsdA==", f.Salt)
+	assert.True(t, f.Data == data, "data mismatch")
+
+	// The client is still usable.
+	v, err := c.Version()
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.12.2", v.Version)
+}
+
+func TestCmdsSnapshot(t *testing.T) {
+	var lines []string
+	s := newServer(t,
+		handler("serversnapshotcreate", func(line string) string {
+			return `version=3 data=KLUv\/aTFeAEAjeAA salt=c2FsdA==`
+		}),
+		handler("serversnapshotdeploy", func(line string) string {
+			lines = append(lines, line)
+			return `sid=2|ocid=1 ncid=5|ocid=2 ncid=6`
+		}),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	var buf bytes.Buffer
+	snapshotwrite := func(t *testing.T) {
+		t.Helper()
+		if !assert.NoError(t, c.Server.SnapshotWrite(&buf, "secret")) {
+			return
+		}
+
+		f, err := SnapshotRead(bytes.NewReader(buf.Bytes()))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 3, f.Version)
+		assert.Equal(t, "c2FsdA==", f.Salt)
+		assert.Equal(t, "KLUv/aTFeAEAjeAA", f.Data)
+		assert.False(t, f.Created.IsZero())
+	}
+
+	snapshotdeploy := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		res, err := c.Server.SnapshotDeployFrom(bytes.NewReader(buf.Bytes()), "secret", &DeployOptions{KeepFiles: true, Mapping: true})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, &DeployResult{ServerID: 2, Channels: map[int]int{1: 5, 2: 6}}, res)
+
+		_, err = c.Server.SnapshotDeployFrom(bytes.NewReader(buf.Bytes()), "secret", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{
+			`serversnapshotdeploy password=secret version=3 salt=c2FsdA== data=KLUv\/aTFeAEAjeAA -keepfiles -mapping`,
+			`serversnapshotdeploy password=secret version=3 salt=c2FsdA== data=KLUv\/aTFeAEAjeAA`,
+		}, lines)
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"snapshotwrite", snapshotwrite},
+		{"snapshotdeploy", snapshotdeploy},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.f(t)
+		})
+	}
+}
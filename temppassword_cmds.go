@@ -0,0 +1,55 @@
+package ts3
+
+import (
+	"time"
+)
+
+// TempPassword represents a temporary server password.
+type TempPassword struct {
+	Nickname         string    `ms:"nickname"` // Nickname is the nickname of the client which created the password.
+	UniqueIdentifier string    `ms:"uid"`      // UniqueIdentifier is the UID of the client which created the password.
+	Description      string    `ms:"desc"`
+	Password         string    `ms:"pw_clear"`
+	Start            time.Time `ms:"start"`
+	End              time.Time `ms:"end"`
+	TargetChannelID  int       `ms:"tcid"` // TargetChannelID is the channel clients join with, 0 for the default channel.
+}
+
+// Duration returns how long the password is valid for.
+func (tp *TempPassword) Duration() time.Duration {
+	return tp.End.Sub(tp.Start)
+}
+
+// TempPasswordAdd adds the temporary password pw to the selected virtual server,
+// which is valid for the duration d, truncated to seconds. Clients which connect
+// with it join the channel tcid, using the channel password tcpw if needed.
+// If tcid is 0 clients join the default channel.
+func (s *ServerMethods) TempPasswordAdd(pw, desc string, d time.Duration, tcid int, tcpw string) error {
+	_, err := s.ExecCmd(NewCmd("servertemppasswordadd").WithArgs(
+		NewArg("pw", pw),
+		NewArg("desc", desc),
+		NewArg("duration", int64(d/time.Second)),
+		NewArg("tcid", tcid),
+		NewArg("tcpw", tcpw),
+	))
+	return err
+}
+
+// TempPasswordDel deletes the temporary password pw from the selected virtual server.
+func (s *ServerMethods) TempPasswordDel(pw string) error {
+	_, err := s.ExecCmd(NewCmd("servertemppassworddel").WithArgs(NewArg("pw", pw)))
+	return err
+}
+
+// TempPasswordList returns the temporary passwords of the selected virtual server.
+func (s *ServerMethods) TempPasswordList() ([]*TempPassword, error) {
+	var passwords []*TempPassword
+	if _, err := s.ExecCmd(NewCmd("servertemppasswordlist").WithResponse(&passwords)); err != nil {
+		if isEmptyResult(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return passwords, nil
+}
@@ -0,0 +1,94 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdsTempPassword(t *testing.T) {
+	var lines []string
+	record := func(cmd string) serverOption {
+		return handler(cmd, func(line string) string {
+			lines = append(lines, line)
+			return commands[cmd]
+		})
+	}
+	s := newServer(t,
+		record("servertemppasswordadd"),
+		record("servertemppassworddel"),
+	)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	temppasswordadd := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		if !assert.NoError(t, c.Server.TempPasswordAdd("s3cret", "match 42", time.Hour, 39, "")) {
+			return
+		}
+		assert.Equal(t, []string{`servertemppasswordadd pw=s3cret desc=match\s42 duration=3600 tcid=39 tcpw=`}, lines)
+	}
+
+	temppassworddel := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		if !assert.NoError(t, c.Server.TempPasswordDel("s3cret")) {
+			return
+		}
+		assert.Equal(t, []string{`servertemppassworddel pw=s3cret`}, lines)
+	}
+
+	temppasswordlist := func(t *testing.T) {
+		t.Helper()
+		passwords, err := c.Server.TempPasswordList()
+		if !assert.NoError(t, err) {
+			return
+		}
+		expected := []*TempPassword{
+			{
+				Nickname:         "serveradmin",
+				UniqueIdentifier: "serveradmin",
+				Description:      "match 42",
+				Password:         "s3cret",
+				Start:            time.Unix(1499948005, 0),
+				End:              time.Unix(1499951605, 0),
+				TargetChannelID:  39,
+			},
+			{
+				Nickname:         "bdeb1337",
+				UniqueIdentifier: "fBdHMWGZ6Nkd3wB7RITGNWGkbOw=",
+				Password:         "guest",
+				Start:            time.Unix(1499948005, 0),
+				End:              time.Unix(1499948065, 0),
+			},
+		}
+		assert.Equal(t, expected, passwords)
+		assert.Equal(t, time.Hour, passwords[0].Duration())
+		assert.Equal(t, time.Minute, passwords[1].Duration())
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"temppasswordadd", temppasswordadd},
+		{"temppassworddel", temppassworddel},
+		{"temppasswordlist", temppasswordlist},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.f(t)
+		})
+	}
+}
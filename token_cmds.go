@@ -0,0 +1,109 @@
+package ts3
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TokenType is the type of a privilege key.
+type TokenType int
+
+const (
+	// TokenServerGroup is a privilege key which grants a server group.
+	TokenServerGroup TokenType = 0
+
+	// TokenChannelGroup is a privilege key which grants a channel group in a channel.
+	TokenChannelGroup TokenType = 1
+)
+
+// String implements fmt.Stringer.
+func (t TokenType) String() string {
+	switch t {
+	case TokenServerGroup:
+		return "server group"
+	case TokenChannelGroup:
+		return "channel group"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(t))
+	}
+}
+
+// TokenSpec describes a privilege key to create with PrivilegeKeyCreate.
+// Use NewServerGroupToken or NewChannelGroupToken to create one.
+type TokenSpec struct {
+	Type        TokenType
+	ID1         int
+	ID2         int
+	Description string
+
+	// CustomSet is a set of custom client properties, identifier to value,
+	// which are assigned to the client which uses the key.
+	CustomSet map[string]string
+}
+
+// NewServerGroupToken returns a TokenSpec for a privilege key which grants the server group sgid.
+func NewServerGroupToken(sgid int) *TokenSpec {
+	return &TokenSpec{Type: TokenServerGroup, ID1: sgid}
+}
+
+// NewChannelGroupToken returns a TokenSpec for a privilege key which grants
+// the channel group cgid in the channel cid.
+func NewChannelGroupToken(cgid, cid int) *TokenSpec {
+	return &TokenSpec{Type: TokenChannelGroup, ID1: cgid, ID2: cid}
+}
+
+// encodeCustomSet returns set encoded as the value of the tokencustomset argument,
+// ordered by identifier.
+func encodeCustomSet(set map[string]string) string {
+	idents := make([]string, 0, len(set))
+	for ident := range set {
+		idents = append(idents, ident)
+	}
+	sort.Strings(idents)
+
+	// Each entry is itself a set of arguments so its values are encoded
+	// before the whole value is encoded as an argument.
+	entries := make([]string, len(idents))
+	for i, ident := range idents {
+		entries[i] = NewArgSet(NewArg("ident", ident), NewArg("value", set[ident])).ArgString()
+	}
+
+	return strings.Join(entries, "|")
+}
+
+// PrivilegeKeyCreate creates a new privilege key on the selected server and returns it.
+func (s *ServerMethods) PrivilegeKeyCreate(spec *TokenSpec) (string, error) {
+	args := []CmdArg{
+		NewArg("tokentype", int(spec.Type)),
+		NewArg("tokenid1", spec.ID1),
+		NewArg("tokenid2", spec.ID2),
+	}
+	if spec.Description != "" {
+		args = append(args, NewArg("tokendescription", spec.Description))
+	}
+	if len(spec.CustomSet) > 0 {
+		args = append(args, NewArg("tokencustomset", encodeCustomSet(spec.CustomSet)))
+	}
+
+	t := struct {
+		Token string
+	}{}
+	if _, err := s.ExecCmd(NewCmd("privilegekeyadd").WithArgs(args...).WithResponse(&t)); err != nil {
+		return "", err
+	}
+
+	return t.Token, nil
+}
+
+// PrivilegeKeyDelete deletes the privilege key token.
+func (s *ServerMethods) PrivilegeKeyDelete(token string) error {
+	_, err := s.ExecCmd(NewCmd("privilegekeydelete").WithArgs(NewArg("token", token)))
+	return err
+}
+
+// PrivilegeKeyUse uses the privilege key token to gain access to its group for the query client.
+func (s *ServerMethods) PrivilegeKeyUse(token string) error {
+	_, err := s.ExecCmd(NewCmd("privilegekeyuse").WithArgs(NewArg("token", token)))
+	return err
+}
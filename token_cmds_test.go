@@ -0,0 +1,79 @@
+package ts3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdsToken(t *testing.T) {
+	var lines []string
+	s := newServer(t, handler("privilegekeyadd", func(line string) string {
+		lines = append(lines, line)
+		return commands["privilegekeyadd"]
+	}))
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	c, err := NewClient(s.Addr, Timeout(time.Second*2))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, c.Close())
+	}()
+
+	privilegekeycreate := func(t *testing.T) {
+		t.Helper()
+		lines = nil
+		token, err := c.Server.PrivilegeKeyCreate(NewServerGroupToken(6))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "zTfamFVhiMEzhTl49KrOVYaMilHPgQEBQOJFh6qX", token)
+
+		spec := NewChannelGroupToken(5, 39)
+		spec.Description = "channel admin"
+		spec.CustomSet = map[string]string{"forum_user": "some one", "forum_id": "123"}
+		_, err = c.Server.PrivilegeKeyCreate(spec)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		expected := []string{
+			"privilegekeyadd tokentype=0 tokenid1=6 tokenid2=0",
+			`privilegekeyadd tokentype=1 tokenid1=5 tokenid2=39 tokendescription=channel\sadmin tokencustomset=ident=forum_id\svalue=123\pident=forum_user\svalue=some\\sone`,
+		}
+		assert.Equal(t, expected, lines)
+	}
+
+	privilegekeydelete := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.PrivilegeKeyDelete("zTfamFVhiMEzhTl49KrOVYaMilHPgQEBQOJFh6qX"))
+	}
+
+	privilegekeyuse := func(t *testing.T) {
+		t.Helper()
+		assert.NoError(t, c.Server.PrivilegeKeyUse("zTfamFVhiMEzhTl49KrOVYaMilHPgQEBQOJFh6qX"))
+	}
+
+	tests := []struct {
+		name string
+		f    func(t *testing.T)
+	}{
+		{"privilegekeycreate", privilegekeycreate},
+		{"privilegekeydelete", privilegekeydelete},
+		{"privilegekeyuse", privilegekeyuse},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, tc.f)
+	}
+}
+
+func TestTokenTypeString(t *testing.T) {
+	assert.Equal(t, "server group", TokenServerGroup.String())
+	assert.Equal(t, "channel group", TokenChannelGroup.String())
+	assert.Equal(t, "unknown (7)", TokenType(7).String())
+}
@@ -0,0 +1,422 @@
+// Package tokens manages the lifecycle of TeamSpeak 3 privilege keys which
+// are only valid for a limited time.
+//
+// The server has no concept of privilege key expiry so a Manager records the
+// expiry in the key description, which is visible to server administrators,
+// and deletes expired keys on a schedule. It also records tokenused
+// notifications so that the group membership granted by a key can be revoked
+// once the membership time to live set by Membership has passed since it
+// was used.
+//
+// The client must be registered for token events to track redemptions:
+//
+//	c.Register(ts3.TokenUsedEvents)
+//	m.Run(ctx, c.Notifications(), time.Minute)
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+)
+
+var (
+	// ErrInvalidTTL is returned by Issue and Membership if the time to live isn't positive.
+	ErrInvalidTTL = errors.New("invalid ttl")
+
+	// ErrNoMembership is returned by New if Revoke is set without Membership.
+	ErrNoMembership = errors.New("no membership ttl")
+
+	// expiresRe matches the expiry recorded in a description.
+	expiresRe = regexp.MustCompile(`^(.*?) ?\(expires ([^)]+)\)$`)
+)
+
+// FormatDescription returns desc with the expiry time appended.
+func FormatDescription(desc string, expires time.Time) string {
+	e := fmt.Sprintf("(expires %s)", expires.UTC().Format(time.RFC3339))
+	if desc == "" {
+		return e
+	}
+	return desc + " " + e
+}
+
+// ParseDescription returns the original description and expiry time from a
+// description created by FormatDescription. If s has no expiry, ok is false
+// and desc is s.
+func ParseDescription(s string) (desc string, expires time.Time, ok bool) {
+	m := expiresRe.FindStringSubmatch(s)
+	if m == nil {
+		return s, time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339, m[2])
+	if err != nil {
+		return s, time.Time{}, false
+	}
+
+	return m[1], t, true
+}
+
+// Server is the subset of ts3.ServerMethods used by a Manager.
+type Server interface {
+	PrivilegeKeyCreate(spec *ts3.TokenSpec) (string, error)
+	PrivilegeKeyList() ([]*ts3.PrivilegeKey, error)
+	PrivilegeKeyDelete(token string) error
+}
+
+// Token is a privilege key managed by a Manager.
+type Token struct {
+	Key         *ts3.PrivilegeKey
+	Description string // Description is the key description without the expiry.
+
+	// Expires is when the key expires, zero if the key has no expiry.
+	Expires time.Time
+}
+
+// Expired returns true if the token has an expiry before now.
+func (t *Token) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && !now.Before(t.Expires)
+}
+
+// Redemption records the use of a privilege key by a client.
+type Redemption struct {
+	Time             time.Time
+	Token            string
+	ClientID         int
+	DatabaseID       int
+	UniqueIdentifier string
+	GroupID          int // GroupID is the server or channel group granted.
+	ChannelID        int // ChannelID is the channel for channel group keys, zero otherwise.
+
+	// KeyExpires is the expiry of the key used, zero if unknown or it had none.
+	KeyExpires time.Time
+
+	// Expires is when the membership granted ends, zero if the key isn't
+	// managed or no membership time to live is set.
+	Expires time.Time
+
+	// Revoked is true once the Manager has revoked the membership granted.
+	Revoked bool
+}
+
+// RevokeFunc revokes the group membership granted by a redemption.
+type RevokeFunc func(r *Redemption) error
+
+// ServerGroupRemover is the subset of ts3.ServerMethods used by RevokeServerGroup.
+type ServerGroupRemover interface {
+	ServerGroupDelClient(sgid int, cldbids ...int) error
+}
+
+// RevokeServerGroup returns a RevokeFunc which removes the client from the
+// server group granted by a server group key. Channel group redemptions are ignored.
+func RevokeServerGroup(server ServerGroupRemover) RevokeFunc {
+	return func(r *Redemption) error {
+		if r.ChannelID != 0 {
+			return nil
+		}
+		return server.ServerGroupDelClient(r.GroupID, r.DatabaseID)
+	}
+}
+
+// Manager issues privilege keys with an expiry and cleans them up.
+type Manager struct {
+	server     Server
+	revoke     RevokeFunc
+	onRedeem   func(*Redemption)
+	onError    func(error)
+	now        func() time.Time
+	membership time.Duration
+
+	mtx         sync.Mutex
+	expires     map[string]time.Time // expires is the known expiry of outstanding keys.
+	redemptions []*Redemption        // redemptions are those pending revocation.
+}
+
+// Membership sets how long the group membership granted by a managed key
+// lasts after the key is used. It's required if Revoke is set.
+func Membership(ttl time.Duration) func(*Manager) error {
+	return func(m *Manager) error {
+		if ttl <= 0 {
+			return ErrInvalidTTL
+		}
+		m.membership = ttl
+		return nil
+	}
+}
+
+// Revoke sets the function used to revoke the membership granted by a key
+// once it ends, see Membership.
+func Revoke(f RevokeFunc) func(*Manager) error {
+	return func(m *Manager) error {
+		m.revoke = f
+		return nil
+	}
+}
+
+// OnRedeem sets a function which is called when a key is used.
+func OnRedeem(f func(*Redemption)) func(*Manager) error {
+	return func(m *Manager) error {
+		m.onRedeem = f
+		return nil
+	}
+}
+
+// Errors sets a function which is called with errors which occur while running.
+func Errors(f func(error)) func(*Manager) error {
+	return func(m *Manager) error {
+		m.onError = f
+		return nil
+	}
+}
+
+// New returns a new Manager which manages keys using server.
+func New(server Server, options ...func(*Manager) error) (*Manager, error) {
+	m := &Manager{
+		server:   server,
+		onRedeem: func(*Redemption) {},
+		onError:  func(error) {},
+		now:      time.Now,
+		expires:  make(map[string]time.Time),
+	}
+	for _, f := range options {
+		if f == nil {
+			return nil, ts3.ErrNilOption
+		}
+		if err := f(m); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.revoke != nil && m.membership == 0 {
+		return nil, ErrNoMembership
+	}
+
+	return m, nil
+}
+
+// Issue creates a privilege key from spec which expires after ttl.
+func (m *Manager) Issue(spec *ts3.TokenSpec, ttl time.Duration) (*Token, error) {
+	if ttl <= 0 {
+		return nil, ErrInvalidTTL
+	}
+
+	expires := m.now().Add(ttl).Truncate(time.Second)
+	s := *spec
+	s.Description = FormatDescription(spec.Description, expires)
+
+	key, err := m.server.PrivilegeKeyCreate(&s)
+	if err != nil {
+		return nil, fmt.Errorf("tokens: issue: %w", err)
+	}
+
+	m.mtx.Lock()
+	m.expires[key] = expires
+	m.mtx.Unlock()
+
+	return &Token{
+		Key: &ts3.PrivilegeKey{
+			Token:       key,
+			Type:        s.Type,
+			ID1:         s.ID1,
+			ID2:         s.ID2,
+			Created:     m.now(),
+			Description: s.Description,
+		},
+		Description: spec.Description,
+		Expires:     expires,
+	}, nil
+}
+
+// List returns the privilege keys on the server with their expiry, if any.
+func (m *Manager) List() ([]*Token, error) {
+	keys, err := m.server.PrivilegeKeyList()
+	if err != nil {
+		return nil, fmt.Errorf("tokens: list: %w", err)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	tokens := make([]*Token, len(keys))
+	for i, k := range keys {
+		t := &Token{Key: k}
+		t.Description, t.Expires, _ = ParseDescription(k.Description)
+		if !t.Expires.IsZero() {
+			m.expires[k.Token] = t.Expires
+		}
+		tokens[i] = t
+	}
+
+	return tokens, nil
+}
+
+// DeleteExpired deletes the expired keys from the server and returns them.
+// Keys without an expiry are never deleted.
+func (m *Manager) DeleteExpired() ([]*Token, error) {
+	tokens, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.now()
+	var deleted []*Token
+	for _, t := range tokens {
+		if !t.Expired(now) {
+			continue
+		}
+
+		if err := m.server.PrivilegeKeyDelete(t.Key.Token); err != nil {
+			return deleted, fmt.Errorf("tokens: delete %v: %w", t.Key.Token, err)
+		}
+
+		m.mtx.Lock()
+		delete(m.expires, t.Key.Token)
+		m.mtx.Unlock()
+		deleted = append(deleted, t)
+	}
+
+	return deleted, nil
+}
+
+// Redemptions returns the redemptions whose membership is pending revocation
+// ordered by time. Redemptions are only recorded if a Revoke function is set.
+func (m *Manager) Redemptions() []*Redemption {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	r := make([]*Redemption, len(m.redemptions))
+	copy(r, m.redemptions)
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Time.Before(r[j].Time)
+	})
+
+	return r
+}
+
+// RevokeExpired calls the Revoke function for redemptions whose membership
+// has ended and removes them, it does nothing if no Revoke function is set.
+// Redemptions which fail to revoke are retried on the next call.
+func (m *Manager) RevokeExpired() error {
+	if m.revoke == nil {
+		return nil
+	}
+
+	now := m.now()
+	m.mtx.Lock()
+	var pending []*Redemption
+	for _, r := range m.redemptions {
+		if !now.Before(r.Expires) {
+			pending = append(pending, r)
+		}
+	}
+	m.mtx.Unlock()
+
+	var errs []error
+	for _, r := range pending {
+		if err := m.revoke(r); err != nil {
+			errs = append(errs, fmt.Errorf("tokens: revoke %v for %v: %w", r.Token, r.UniqueIdentifier, err))
+			continue
+		}
+
+		m.mtx.Lock()
+		r.Revoked = true
+		m.remove(r)
+		m.mtx.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// remove removes r from the pending redemptions, m.mtx must be held.
+func (m *Manager) remove(r *Redemption) {
+	for i, p := range m.redemptions {
+		if p == r {
+			m.redemptions = append(m.redemptions[:i], m.redemptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// Handle records the redemption in a tokenused notification.
+// Other notifications are ignored.
+func (m *Manager) Handle(n ts3.Notification) {
+	if n.Type != "tokenused" {
+		return
+	}
+
+	r := &Redemption{
+		Time:             m.now(),
+		Token:            n.Data["token"],
+		ClientID:         atoi(n.Data["clid"]),
+		DatabaseID:       atoi(n.Data["cldbid"]),
+		UniqueIdentifier: n.Data["cluid"],
+		GroupID:          atoi(n.Data["token1"]),
+		ChannelID:        atoi(n.Data["token2"]),
+	}
+
+	m.mtx.Lock()
+	// Keys are deleted by the server once used.
+	r.KeyExpires = m.expires[r.Token]
+	delete(m.expires, r.Token)
+	if !r.KeyExpires.IsZero() && m.membership > 0 {
+		r.Expires = r.Time.Add(m.membership)
+		if m.revoke != nil {
+			m.redemptions = append(m.redemptions, r)
+		}
+	}
+	m.mtx.Unlock()
+
+	m.onRedeem(r)
+}
+
+// Run handles notifications from ch and, every interval, deletes expired keys
+// and revokes ended memberships until ctx is done or ch is closed.
+// Errors which occur are passed to the Errors function.
+func (m *Manager) Run(ctx context.Context, ch <-chan ts3.Notification, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Populate the known expiries so redemptions of existing keys are tracked.
+	m.tick()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("tokens: run: %w", ctx.Err())
+		case n, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			m.Handle(n)
+		case <-ticker.C:
+			m.tick()
+		}
+	}
+}
+
+// tick performs the scheduled cleanup.
+func (m *Manager) tick() {
+	if _, err := m.DeleteExpired(); err != nil {
+		m.onError(err)
+	}
+	if err := m.RevokeExpired(); err != nil {
+		m.onError(err)
+	}
+}
+
+// atoi returns s as an int or 0 if it's not valid.
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
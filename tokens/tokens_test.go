@@ -0,0 +1,325 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/honeybbq/go-ts3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is an in memory Server.
+type fakeServer struct {
+	keys       []*ts3.PrivilegeKey
+	next       int
+	failDelete bool
+	removed    map[int][]int // sgid -> cldbids
+}
+
+func (f *fakeServer) PrivilegeKeyCreate(spec *ts3.TokenSpec) (string, error) {
+	f.next++
+	token := fmt.Sprintf("token%d", f.next)
+	f.keys = append(f.keys, &ts3.PrivilegeKey{
+		Token:       token,
+		Type:        spec.Type,
+		ID1:         spec.ID1,
+		ID2:         spec.ID2,
+		Description: spec.Description,
+	})
+	return token, nil
+}
+
+func (f *fakeServer) PrivilegeKeyList() ([]*ts3.PrivilegeKey, error) {
+	return f.keys, nil
+}
+
+func (f *fakeServer) PrivilegeKeyDelete(token string) error {
+	if f.failDelete {
+		return &ts3.Error{ID: int(ts3.ErrPermissionClientInsufficient), Msg: "insufficient client permissions"}
+	}
+	for i, k := range f.keys {
+		if k.Token == token {
+			f.keys = append(f.keys[:i], f.keys[i+1:]...)
+			return nil
+		}
+	}
+	return &ts3.Error{ID: int(ts3.ErrPrivilegeKeyInvalid), Msg: "invalid privilege key"}
+}
+
+func (f *fakeServer) ServerGroupDelClient(sgid int, cldbids ...int) error {
+	if f.removed == nil {
+		f.removed = make(map[int][]int)
+	}
+	f.removed[sgid] = append(f.removed[sgid], cldbids...)
+	return nil
+}
+
+// use simulates a client using the key token, returning the notification sent.
+func (f *fakeServer) use(token string, cldbid int) ts3.Notification {
+	for i, k := range f.keys {
+		if k.Token == token {
+			f.keys = append(f.keys[:i], f.keys[i+1:]...)
+			return ts3.Notification{
+				Type: "tokenused",
+				Data: map[string]string{
+					"clid":   "5",
+					"cldbid": fmt.Sprint(cldbid),
+					"cluid":  "uid" + fmt.Sprint(cldbid),
+					"token":  token,
+					"token1": fmt.Sprint(k.ID1),
+					"token2": fmt.Sprint(k.ID2),
+				},
+			}
+		}
+	}
+	return ts3.Notification{}
+}
+
+// clock is a manually advanced time source.
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time { return c.t }
+
+func newManager(t *testing.T, f *fakeServer, options ...func(*Manager) error) (*Manager, *clock) {
+	t.Helper()
+
+	m, err := New(f, options...)
+	require.NoError(t, err)
+
+	c := &clock{t: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	m.now = c.now
+
+	return m, c
+}
+
+func TestDescription(t *testing.T) {
+	expires := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		desc string
+		want string
+	}{
+		{name: "empty", want: "(expires 2020-01-02T03:04:05Z)"},
+		{name: "text", desc: "guest pass", want: "guest pass (expires 2020-01-02T03:04:05Z)"},
+		{name: "parentheses", desc: "pass (vip)", want: "pass (vip) (expires 2020-01-02T03:04:05Z)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := FormatDescription(tc.desc, expires)
+			assert.Equal(t, tc.want, s)
+
+			desc, exp, ok := ParseDescription(s)
+			assert.True(t, ok)
+			assert.Equal(t, tc.desc, desc)
+			assert.True(t, expires.Equal(exp))
+		})
+	}
+
+	desc, exp, ok := ParseDescription("no expiry")
+	assert.False(t, ok)
+	assert.Equal(t, "no expiry", desc)
+	assert.True(t, exp.IsZero())
+
+	_, _, ok = ParseDescription("bad (expires tomorrow)")
+	assert.False(t, ok)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&fakeServer{}, nil)
+	assert.Equal(t, ts3.ErrNilOption, err)
+
+	_, err = New(&fakeServer{}, Membership(0))
+	assert.Equal(t, ErrInvalidTTL, err)
+
+	_, err = New(&fakeServer{}, Revoke(func(*Redemption) error { return nil }))
+	assert.Equal(t, ErrNoMembership, err)
+}
+
+func TestIssueDeleteExpired(t *testing.T) {
+	f := &fakeServer{}
+	m, c := newManager(t, f)
+
+	_, err := m.Issue(ts3.NewServerGroupToken(10), 0)
+	assert.Equal(t, ErrInvalidTTL, err)
+
+	spec := ts3.NewServerGroupToken(10)
+	spec.Description = "guest"
+	short, err := m.Issue(spec, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "token1", short.Key.Token)
+	assert.Equal(t, "guest", short.Description)
+	assert.Equal(t, c.t.Add(time.Hour), short.Expires)
+	assert.Equal(t, "guest", spec.Description, "spec modified")
+
+	long, err := m.Issue(ts3.NewChannelGroupToken(5, 1), 24*time.Hour)
+	require.NoError(t, err)
+
+	// Keys without an expiry are left alone.
+	f.keys = append(f.keys, &ts3.PrivilegeKey{Token: "manual", Description: "manual"})
+
+	tokens, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, tokens, 3)
+	assert.Equal(t, "guest", tokens[0].Description)
+	assert.Equal(t, short.Expires, tokens[0].Expires)
+	assert.True(t, tokens[2].Expires.IsZero())
+
+	deleted, err := m.DeleteExpired()
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	c.t = c.t.Add(time.Hour)
+	deleted, err = m.DeleteExpired()
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, short.Key.Token, deleted[0].Key.Token)
+
+	c.t = c.t.Add(48 * time.Hour)
+	deleted, err = m.DeleteExpired()
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, long.Key.Token, deleted[0].Key.Token)
+
+	require.Len(t, f.keys, 1)
+	assert.Equal(t, "manual", f.keys[0].Token)
+
+	f.keys = append(f.keys, &ts3.PrivilegeKey{Token: "old", Description: FormatDescription("", c.t)})
+	f.failDelete = true
+	_, err = m.DeleteExpired()
+	var e *ts3.Error
+	assert.True(t, errors.As(err, &e))
+}
+
+func TestRedemptions(t *testing.T) {
+	f := &fakeServer{}
+	var redeemed []*Redemption
+	m, c := newManager(t, f,
+		OnRedeem(func(r *Redemption) { redeemed = append(redeemed, r) }),
+		Membership(30*time.Minute),
+		Revoke(RevokeServerGroup(f)),
+	)
+
+	sg, err := m.Issue(ts3.NewServerGroupToken(10), time.Hour)
+	require.NoError(t, err)
+	cg, err := m.Issue(ts3.NewChannelGroupToken(5, 1), time.Hour)
+	require.NoError(t, err)
+	f.keys = append(f.keys, &ts3.PrivilegeKey{Token: "manual", ID1: 11})
+
+	m.Handle(ts3.Notification{Type: "cliententerview"})
+	m.Handle(f.use(sg.Key.Token, 7))
+	m.Handle(f.use("manual", 9))
+
+	// Used just before the key expires, the membership still lasts its full time.
+	c.t = c.t.Add(59 * time.Minute)
+	m.Handle(f.use(cg.Key.Token, 8))
+
+	require.Len(t, redeemed, 3)
+	assert.Equal(t, &Redemption{
+		Time:             c.t.Add(-59 * time.Minute),
+		Token:            sg.Key.Token,
+		ClientID:         5,
+		DatabaseID:       7,
+		UniqueIdentifier: "uid7",
+		GroupID:          10,
+		KeyExpires:       sg.Expires,
+		Expires:          c.t.Add(-29 * time.Minute),
+	}, redeemed[0])
+	assert.True(t, redeemed[1].Expires.IsZero(), "unmanaged key")
+	assert.Equal(t, 1, redeemed[2].ChannelID)
+	assert.Equal(t, c.t.Add(30*time.Minute), redeemed[2].Expires)
+
+	// Unmanaged keys aren't tracked.
+	assert.Equal(t, []*Redemption{redeemed[0], redeemed[2]}, m.Redemptions())
+
+	require.NoError(t, m.RevokeExpired())
+	assert.Equal(t, map[int][]int{10: {7}}, f.removed)
+	assert.True(t, redeemed[0].Revoked)
+	assert.False(t, redeemed[2].Revoked)
+	assert.Equal(t, []*Redemption{redeemed[2]}, m.Redemptions())
+
+	// The key has expired but the membership hasn't.
+	c.t = c.t.Add(2 * time.Minute)
+	require.NoError(t, m.RevokeExpired())
+	assert.False(t, redeemed[2].Revoked)
+
+	c.t = c.t.Add(28 * time.Minute)
+	require.NoError(t, m.RevokeExpired())
+	assert.True(t, redeemed[2].Revoked)
+	assert.Empty(t, m.Redemptions())
+
+	// Already revoked.
+	require.NoError(t, m.RevokeExpired())
+	assert.Equal(t, map[int][]int{10: {7}}, f.removed)
+}
+
+func TestRevokeError(t *testing.T) {
+	f := &fakeServer{}
+	fail := true
+	var calls int
+	m, c := newManager(t, f, Membership(time.Hour), Revoke(func(r *Redemption) error {
+		calls++
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	}))
+
+	tok, err := m.Issue(ts3.NewServerGroupToken(10), time.Hour)
+	require.NoError(t, err)
+	m.Handle(f.use(tok.Key.Token, 7))
+
+	c.t = c.t.Add(time.Hour)
+	assert.Error(t, m.RevokeExpired())
+
+	fail = false
+	require.NoError(t, m.RevokeExpired())
+	require.NoError(t, m.RevokeExpired())
+	assert.Equal(t, 2, calls)
+}
+
+func TestRun(t *testing.T) {
+	f := &fakeServer{}
+	f.keys = []*ts3.PrivilegeKey{
+		{Token: "expired", Description: FormatDescription("", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{Token: "valid", ID1: 10, Description: FormatDescription("", time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC))},
+	}
+
+	redeemed := make(chan *Redemption, 1)
+	m, _ := newManager(t, f, OnRedeem(func(r *Redemption) { redeemed <- r }))
+
+	n := ts3.Notification{Type: "tokenused", Data: map[string]string{"cldbid": "7", "token": "valid", "token1": "10"}}
+	ch := make(chan ts3.Notification, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- m.Run(ctx, ch, time.Hour)
+	}()
+
+	// The initial cleanup runs before notifications are handled.
+	ch <- n
+	select {
+	case r := <-redeemed:
+		assert.Equal(t, "valid", r.Token)
+		assert.Equal(t, time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), r.KeyExpires)
+	case <-time.After(time.Second):
+		t.Fatal("no redemption")
+	}
+	require.Len(t, f.keys, 1)
+	assert.Equal(t, "valid", f.keys[0].Token)
+
+	cancel()
+	err := <-done
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	m, _ = newManager(t, f)
+	close(ch)
+	assert.NoError(t, m.Run(context.Background(), ch, time.Hour))
+}
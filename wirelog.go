@@ -0,0 +1,142 @@
+package ts3
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedactedValue replaces the value of secrets in wire log lines.
+const RedactedValue = "[REDACTED]"
+
+// redactRe matches the arguments whose values are redacted by the wire log:
+// login, snapshot, temporary and channel passwords, privilege keys and file
+// transfer keys.
+var redactRe = regexp.MustCompile(`(^|[ |])(client_login_password|password|pw|pw_clear|tcpw|cpw|` +
+	`channel_password|virtualserver_password|token|ftkey)=[^ |]*`)
+
+// loginRe matches the password of a login with positional arguments,
+// login {username} {password}.
+var loginRe = regexp.MustCompile(`^(login +[^ |=]+ +)[^ |]+`)
+
+// Redact returns line with the values of secret arguments replaced by RedactedValue.
+func Redact(line string) string {
+	line = loginRe.ReplaceAllString(line, "${1}"+RedactedValue)
+	return redactRe.ReplaceAllString(line, "${1}${2}="+RedactedValue)
+}
+
+// WireLog logs every line sent to and received from the server to logger
+// at debug level. Secrets are redacted, see Redact.
+//
+// Each entry has a dir attribute of send or recv and a seq attribute which
+// correlates responses with the command which caused them. The final line
+// of a response also has the elapsed time since the command was sent.
+// Notifications and keep alives have no seq.
+func WireLog(logger *slog.Logger) func(*Client) error {
+	return func(c *Client) error {
+		if logger == nil {
+			return ErrNilOption
+		}
+		c.wire = &wireLogger{logger: logger}
+		return nil
+	}
+}
+
+// wireLogger logs the lines sent and received by a Client.
+type wireLogger struct {
+	logger *slog.Logger
+
+	mtx  sync.Mutex
+	seq  uint64    // seq is the sequence number of the last command sent.
+	cmd  string    // cmd is the name of the last command sent.
+	sent time.Time // sent is when the last command was sent.
+}
+
+// enabled returns true if w is non nil and debug logging is enabled.
+func (w *wireLogger) enabled() bool {
+	return w != nil && w.logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// send logs the command line sent to the server.
+func (w *wireLogger) send(line string) {
+	if !w.enabled() {
+		return
+	}
+
+	line = strings.TrimRight(line, "\n")
+	cmd := line
+	if i := strings.IndexByte(cmd, ' '); i >= 0 {
+		cmd = cmd[:i]
+	}
+
+	w.mtx.Lock()
+	w.seq++
+	w.cmd = cmd
+	w.sent = time.Now()
+	seq := w.seq
+	w.mtx.Unlock()
+
+	w.logger.LogAttrs(context.Background(), slog.LevelDebug, "ts3 wire",
+		slog.String("dir", "send"),
+		slog.Uint64("seq", seq),
+		slog.String("cmd", cmd),
+		slog.String("line", Redact(line)),
+	)
+}
+
+// keepAlive logs a keep alive sent to the server.
+func (w *wireLogger) keepAlive() {
+	if !w.enabled() {
+		return
+	}
+
+	w.logger.LogAttrs(context.Background(), slog.LevelDebug, "ts3 wire",
+		slog.String("dir", "send"),
+		slog.String("cmd", "keepalive"),
+	)
+}
+
+// notify logs a notification received from the server.
+func (w *wireLogger) notify(line string) {
+	if !w.enabled() {
+		return
+	}
+
+	event := line
+	if i := strings.IndexByte(event, ' '); i >= 0 {
+		event = event[:i]
+	}
+
+	w.logger.LogAttrs(context.Background(), slog.LevelDebug, "ts3 wire",
+		slog.String("dir", "recv"),
+		slog.String("event", event),
+		slog.String("line", Redact(line)),
+	)
+}
+
+// recv logs a response line received from the server, final is true
+// if it's the error line which completes the response.
+func (w *wireLogger) recv(line string, final bool) {
+	if !w.enabled() {
+		return
+	}
+
+	w.mtx.Lock()
+	seq, cmd, sent := w.seq, w.cmd, w.sent
+	w.mtx.Unlock()
+
+	attrs := []slog.Attr{
+		slog.String("dir", "recv"),
+		slog.Uint64("seq", seq),
+		slog.String("cmd", cmd),
+		slog.String("line", Redact(line)),
+	}
+	if final && !sent.IsZero() {
+		attrs = append(attrs, slog.Duration("elapsed", time.Since(sent)))
+	}
+
+	w.logger.LogAttrs(context.Background(), slog.LevelDebug, "ts3 wire", attrs...)
+}
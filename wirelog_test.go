@@ -0,0 +1,109 @@
+package ts3
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	tests := map[string]string{
+		`login client_login_name=serveradmin client_login_password=secret`: `login client_login_name=serveradmin client_login_password=[REDACTED]`,
+		`serversnapshotcreate password=p\sw`:                               `serversnapshotcreate password=[REDACTED]`,
+		`token=abc+def token_type=0 token_description`:                     `token=[REDACTED] token_type=0 token_description`,
+		`nickname=x pw_clear=p uid=1|nickname=y pw_clear=q`:                `nickname=x pw_clear=[REDACTED] uid=1|nickname=y pw_clear=[REDACTED]`,
+		`servertemppasswordadd pw=p desc=d duration=60 tcid=0 tcpw=c`:      `servertemppasswordadd pw=[REDACTED] desc=d duration=60 tcid=0 tcpw=[REDACTED]`,
+		`clientmove clid=1 cid=2 cpw=x`:                                    `clientmove clid=1 cid=2 cpw=[REDACTED]`,
+		`virtualserver_password=x virtualserver_flag_password=1`:           `virtualserver_password=[REDACTED] virtualserver_flag_password=1`,
+		`virtualserver_name=password=x`:                                    `virtualserver_name=password=x`,
+		`clientftfid=1 serverftfid=2 ftkey=abc port=30033 size=10`:         `clientftfid=1 serverftfid=2 ftkey=[REDACTED] port=30033 size=10`,
+		`login serveradmin secret`:                                         `login serveradmin [REDACTED]`,
+		`login serveradmin se=cr\pet`:                                      `login serveradmin [REDACTED]`,
+		`login serveradmin`:                                                `login serveradmin`,
+	}
+
+	for line, expect := range tests {
+		assert.Equal(t, expect, Redact(line))
+	}
+}
+
+// syncBuffer is a bytes.Buffer which is safe for concurrent use.
+type syncBuffer struct {
+	mtx sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.buf.String()
+}
+
+func TestWireLog(t *testing.T) {
+	s := newServer(t)
+	defer func() {
+		assert.NoError(t, s.Close())
+	}()
+
+	_, err := NewClient(s.Addr, WireLog(nil))
+	assert.Equal(t, ErrNilOption, err)
+
+	var buf syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c, err := NewClient(s.Addr, Timeout(time.Second), WireLog(logger))
+	require.NoError(t, err)
+
+	require.NoError(t, c.Login("serveradmin", "secret"))
+	_, err = c.Server.PrivilegeKeyCreate(NewServerGroupToken(6))
+	require.NoError(t, err)
+	_, err = c.Exec("invalid")
+	assert.Error(t, err)
+	require.NoError(t, c.Close())
+
+	assert.NotContains(t, buf.String(), "secret")
+	assert.NotContains(t, buf.String(), "zTfamFVhiMEzhTl49KrOVYaMilHPgQEBQOJFh6qX")
+
+	type entry struct {
+		Msg     string
+		Dir     string
+		Seq     uint64
+		Cmd     string
+		Line    string
+		Elapsed *int64
+	}
+	var entries []entry
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e entry
+		require.NoError(t, json.Unmarshal([]byte(l), &e))
+		assert.Equal(t, "ts3 wire", e.Msg)
+		entries = append(entries, e)
+	}
+
+	require.Len(t, entries, 9)
+	assert.Equal(t, entry{Msg: "ts3 wire", Dir: "send", Seq: 1, Cmd: "login",
+		Line: "login client_login_name=serveradmin client_login_password=[REDACTED]"}, entries[0])
+	assert.Equal(t, "recv", entries[1].Dir)
+	assert.Equal(t, uint64(1), entries[1].Seq)
+	assert.NotNil(t, entries[1].Elapsed)
+
+	assert.Equal(t, "privilegekeyadd", entries[2].Cmd)
+	assert.Equal(t, entry{Msg: "ts3 wire", Dir: "recv", Seq: 2, Cmd: "privilegekeyadd", Line: "token=[REDACTED]"}, entries[3])
+	assert.NotNil(t, entries[4].Elapsed)
+
+	assert.Equal(t, entry{Msg: "ts3 wire", Dir: "send", Seq: 3, Cmd: "invalid", Line: "invalid"}, entries[5])
+	assert.Equal(t, `error id=256 msg=command\snot\sfound`, entries[6].Line)
+	assert.Equal(t, "quit", entries[7].Cmd)
+}